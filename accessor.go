@@ -1,9 +1,11 @@
 package yoitsu
 
 import (
+	"encoding/json"
 	"fmt"
 	"go/ast"
 	"go/token"
+	"math/big"
 	"slices"
 	"strings"
 )
@@ -48,9 +50,15 @@ func (y *Yoitsu) generateMethodAccessors(gType GeneratedType) (decls []ast.Decl,
 		},
 	}
 
+	// A struct can't be compared to nil, so keep a pointer to know if the data was loaded
+	dataType := gType.Type()
+	if _, ok := gType.(*StructType); ok {
+		dataType = tokenPointer + dataType
+	}
+
 	fieldList.List = append(fieldList.List, &ast.Field{
 		Names: []*ast.Ident{ast.NewIdent(tokenData)},
-		Type:  ast.NewIdent(gType.Type()),
+		Type:  ast.NewIdent(dataType),
 	})
 
 	decls = append(decls, accessorsStruct)
@@ -64,9 +72,19 @@ func (y *Yoitsu) generateMethodAccessors(gType GeneratedType) (decls []ast.Decl,
 	if err != nil {
 		return
 	}
+
+	if checkSrc, ok := y.src.(loadAbleTypeSource); ok {
+		if err = checkSrc.canLoad(gType); err != nil {
+			return
+		}
+	}
 	decls = append(decls, decl)
 	importSpecs = append(importSpecs, importSpec...)
 
+	if declSrc, ok := y.src.(LoadDeclsSource); ok {
+		decls = append(decls, declSrc.LoadDecls()...)
+	}
+
 	decls = append(decls, y.allMethod(gType))
 
 	if y.accessors.ById {
@@ -93,6 +111,12 @@ func (y *Yoitsu) generateMethodAccessors(gType GeneratedType) (decls []ast.Decl,
 	return
 }
 
+// loadAbleTypeSource may be implemented by a LoadAbleSource whose LoadMethod can't always load the data, e.g. not
+// every type
+type loadAbleTypeSource interface {
+	canLoad(gType GeneratedType) error
+}
+
 func (y *Yoitsu) getByIdMethod(gType GeneratedType) ast.Decl {
 	funcName := "ByID"
 
@@ -226,6 +250,14 @@ func (y *Yoitsu) allMethod(gType GeneratedType) ast.Decl {
 		},
 	}
 
+	var data ast.Expr = &ast.SelectorExpr{
+		X:   ast.NewIdent(tokenReceiver),
+		Sel: ast.NewIdent(tokenData),
+	}
+	if _, ok := gType.(*StructType); ok {
+		data = &ast.StarExpr{X: data}
+	}
+
 	funcBody := &ast.BlockStmt{
 		List: []ast.Stmt{
 			&ast.IfStmt{
@@ -277,10 +309,7 @@ func (y *Yoitsu) allMethod(gType GeneratedType) ast.Decl {
 			},
 			&ast.ReturnStmt{
 				Results: []ast.Expr{
-					&ast.SelectorExpr{
-						X:   ast.NewIdent(tokenReceiver),
-						Sel: ast.NewIdent(tokenData),
-					},
+					data,
 					ast.NewIdent("nil"),
 				},
 			},
@@ -322,7 +351,7 @@ func (y *Yoitsu) uniqueGetters(gType StructType, fieldList *ast.FieldList) (decl
 		}
 
 		fieldList.List = append(fieldList.List, &ast.Field{
-			Names: []*ast.Ident{ast.NewIdent(tokenData + toSafeGoName(ujp.Tag))},
+			Names: []*ast.Ident{ast.NewIdent(tokenData + gType.fieldName(ujp.Tag))},
 			Type:  ast.NewIdent(fmt.Sprintf(tokenMap, ujp.Type.Type(), gType.Type())),
 		})
 
@@ -349,7 +378,7 @@ func (y *Yoitsu) groupByMethod(gType StructType, ujps []StructField) ast.Decl {
 			Lhs: []ast.Expr{
 				&ast.SelectorExpr{
 					X:   ast.NewIdent(tokenReceiver),
-					Sel: ast.NewIdent(tokenData + toSafeGoName(ujp.Tag)),
+					Sel: ast.NewIdent(tokenData + gType.fieldName(ujp.Tag)),
 				},
 			},
 			Tok: token.ASSIGN,
@@ -365,11 +394,11 @@ func (y *Yoitsu) groupByMethod(gType StructType, ujps []StructField) ast.Decl {
 				&ast.IndexExpr{
 					X: &ast.SelectorExpr{
 						X:   ast.NewIdent(tokenReceiver),
-						Sel: ast.NewIdent(tokenData + toSafeGoName(ujp.Tag)),
+						Sel: ast.NewIdent(tokenData + gType.fieldName(ujp.Tag)),
 					},
 					Index: &ast.SelectorExpr{
 						X:   ast.NewIdent("d"),
-						Sel: ast.NewIdent(toSafeGoName(ujp.Tag)),
+						Sel: ast.NewIdent(gType.fieldName(ujp.Tag)),
 					},
 				},
 			},
@@ -414,8 +443,11 @@ func (y *Yoitsu) groupByMethod(gType StructType, ujps []StructField) ast.Decl {
 }
 
 func (y *Yoitsu) uniqueJsonPrimitivesAccessor(gType StructType, ujp StructField) ast.Decl {
-	structField := ast.NewIdent(tokenData + toSafeGoName(ujp.Tag))
+	structField := ast.NewIdent(tokenData + gType.fieldName(ujp.Tag))
 	funcName := fmt.Sprintf("By%s", ujp.Tag)
+	if !token.IsIdentifier(funcName) {
+		funcName = "By" + gType.fieldName(ujp.Tag)
+	}
 
 	receiver := &ast.FieldList{
 		List: []*ast.Field{
@@ -550,7 +582,12 @@ func (y *Yoitsu) uniqueJsonPrimitivesAccessor(gType StructType, ujp StructField)
 }
 
 func (y *Yoitsu) uniqueJsonPrimitives(gType StructType) (found []StructField) {
-	data := y.root.([]interface{})
+	values := y.uniqueValues
+	if values == nil {
+		// Not streamed, see WithStreaming
+		values = newUniqueValues()
+		values.addRoot(y.root)
+	}
 
 	for name, field := range gType.Fields {
 		prim, ok := field.Type.(*NativeType)
@@ -558,15 +595,18 @@ func (y *Yoitsu) uniqueJsonPrimitives(gType StructType) (found []StructField) {
 			continue
 		}
 
+		// Pointer fields can't be used as map keys
+		if gType.optional == OptionalFieldsPointer && field.Optional(&gType) && canBePointer(field.Type) {
+			continue
+		}
+
 		switch prim.Type() {
-		case Float64Type.Type():
-			unique, success := extractUniqueValues[float64](data, name)
-			if success && len(unique) == len(data) {
+		case Float64Type.Type(), IntType.Type(), Int64Type.Type():
+			if values.unique(name, uniqueKindNumber) {
 				found = append(found, *field)
 			}
 		case StringType.Type():
-			unique, success := extractUniqueValues[string](data, name)
-			if success && len(unique) == len(data) {
+			if values.unique(name, uniqueKindString) {
 				found = append(found, *field)
 			}
 		}
@@ -575,29 +615,134 @@ func (y *Yoitsu) uniqueJsonPrimitives(gType StructType) (found []StructField) {
 	return
 }
 
-func extractUniqueValues[T comparable](data []interface{}, field string) ([]T, bool) {
-	values := make([]T, 0)
+const (
+	uniqueKindString = "string"
+	uniqueKindNumber = "number"
+)
+
+// uniqueValues collects the primitive values of the fields of the JsonMap's in the root JsonArray, one element at a
+// time. Values are only kept while the field can still be unique, i.e. it was present in every element, always of
+// the same kind and never repeated
+type uniqueValues struct {
+	elements int
+	fields   map[string]*uniqueField
+	// invalid is set if the root isn't a JsonArray of JsonMap's
+	invalid bool
+}
+
+type uniqueField struct {
+	kind   string
+	values map[string]struct{}
+	// done is set once the field can no longer be unique
+	done bool
+}
+
+func newUniqueValues() *uniqueValues {
+	return &uniqueValues{
+		fields: make(map[string]*uniqueField),
+	}
+}
+
+// addRoot adds every element of root, if it is a JsonArray
+func (u *uniqueValues) addRoot(root JsonObject) {
+	if u == nil {
+		return
+	}
 
-	for _, v := range data {
-		d, ok := v.(map[string]any)
+	array, ok := root.(JsonArray)
+	if !ok {
+		u.invalidate()
+		return
+	}
+
+	for _, v := range array {
+		m, ok := v.(JsonMap)
 		if !ok {
-			return nil, false
+			u.invalidate()
+			return
 		}
 
-		value, ok := d[field]
+		element := make(map[string]json.Token, len(m))
+		for k, value := range m {
+			element[k] = value
+		}
+		u.add(element)
+	}
+}
+
+// add adds the values of the fields of one element
+func (u *uniqueValues) add(element map[string]json.Token) {
+	if u == nil || u.invalid {
+		return
+	}
+	u.elements++
+
+	for name, value := range element {
+		field, ok := u.fields[name]
 		if !ok {
+			// Missing in a previous element
+			field = &uniqueField{values: make(map[string]struct{}), done: u.elements > 1}
+			u.fields[name] = field
+		}
+		if field.done {
 			continue
 		}
 
-		typedValue, ok := value.(T)
-		if !ok {
-			return nil, false
+		var kind, key string
+		switch v := value.(type) {
+		case string:
+			kind, key = uniqueKindString, v
+		case json.Number:
+			kind, key = uniqueKindNumber, numberKey(v)
 		}
 
-		if !slices.Contains(values, typedValue) {
-			values = append(values, typedValue)
+		_, repeated := field.values[key]
+		if kind == "" || (field.kind != "" && field.kind != kind) || repeated {
+			field.stop()
+			continue
 		}
+
+		field.kind = kind
+		field.values[key] = struct{}{}
 	}
 
-	return values, true
+	for _, field := range u.fields {
+		if !field.done && len(field.values) < u.elements {
+			// Missing in this element
+			field.stop()
+		}
+	}
+}
+
+// numberKey normalises n, so 1, 1.0 and 1e0 are the same value once unmarshalled
+func numberKey(n json.Number) string {
+	if r, ok := new(big.Rat).SetString(n.String()); ok {
+		return r.RatString()
+	}
+	return n.String()
+}
+
+// invalidate is called when the root, or one of its elements, isn't what Accessors.ById supports
+func (u *uniqueValues) invalidate() {
+	if u == nil {
+		return
+	}
+
+	u.invalid = true
+	clear(u.fields)
+}
+
+// unique returns true if every element had a distinct value of the kind for the field
+func (u *uniqueValues) unique(name string, kind string) bool {
+	if u.invalid {
+		return false
+	}
+
+	field, ok := u.fields[name]
+	return ok && !field.done && field.kind == kind && len(field.values) == u.elements
+}
+
+func (f *uniqueField) stop() {
+	f.done = true
+	f.values = nil
 }
@@ -0,0 +1,188 @@
+package yoitsu
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestUniqueValues(t *testing.T) {
+	type check struct {
+		name   string
+		kind   string
+		unique bool
+	}
+
+	tests := []struct {
+		name     string
+		elements []map[string]json.Token
+		checks   []check
+	}{
+		{
+			name: "distinct values",
+			elements: []map[string]json.Token{
+				{"id": json.Number("1"), "name": "a"},
+				{"id": json.Number("2"), "name": "b"},
+			},
+			checks: []check{
+				{"id", uniqueKindNumber, true},
+				{"name", uniqueKindString, true},
+				{"id", uniqueKindString, false},
+				{"name", uniqueKindNumber, false},
+			},
+		},
+		{
+			name: "repeated value",
+			elements: []map[string]json.Token{
+				{"id": json.Number("1"), "name": "a"},
+				{"id": json.Number("2"), "name": "a"},
+			},
+			checks: []check{
+				{"id", uniqueKindNumber, true},
+				{"name", uniqueKindString, false},
+			},
+		},
+		{
+			name: "repeated number in another notation",
+			elements: []map[string]json.Token{
+				{"id": json.Number("1")},
+				{"id": json.Number("2")},
+				{"id": json.Number("1e0")},
+			},
+			checks: []check{
+				{"id", uniqueKindNumber, false},
+			},
+		},
+		{
+			name: "repeated number with a fraction",
+			elements: []map[string]json.Token{
+				{"id": json.Number("1")},
+				{"id": json.Number("1.0")},
+			},
+			checks: []check{
+				{"id", uniqueKindNumber, false},
+			},
+		},
+		{
+			name: "large distinct numbers",
+			elements: []map[string]json.Token{
+				{"id": json.Number("9007199254740992")},
+				{"id": json.Number("9007199254740993")},
+			},
+			checks: []check{
+				{"id", uniqueKindNumber, true},
+			},
+		},
+		{
+			name: "missing in a later element",
+			elements: []map[string]json.Token{
+				{"id": json.Number("1"), "name": "a"},
+				{"id": json.Number("2")},
+			},
+			checks: []check{
+				{"id", uniqueKindNumber, true},
+				{"name", uniqueKindString, false},
+			},
+		},
+		{
+			name: "missing in an earlier element",
+			elements: []map[string]json.Token{
+				{"id": json.Number("1")},
+				{"id": json.Number("2"), "name": "b"},
+			},
+			checks: []check{
+				{"id", uniqueKindNumber, true},
+				{"name", uniqueKindString, false},
+			},
+		},
+		{
+			name: "mixed kinds",
+			elements: []map[string]json.Token{
+				{"id": json.Number("1")},
+				{"id": "2"},
+			},
+			checks: []check{
+				{"id", uniqueKindNumber, false},
+				{"id", uniqueKindString, false},
+			},
+		},
+		{
+			name: "not a primitive",
+			elements: []map[string]json.Token{
+				{"id": json.Number("1"), "ok": true, "nick": nil},
+				{"id": json.Number("2"), "ok": false, "nick": "b"},
+			},
+			checks: []check{
+				{"id", uniqueKindNumber, true},
+				{"ok", uniqueKindString, false},
+				{"nick", uniqueKindString, false},
+			},
+		},
+		{
+			name: "repeated after stopping",
+			elements: []map[string]json.Token{
+				{"name": "a"},
+				{"name": "a"},
+				{"name": "b"},
+			},
+			checks: []check{
+				{"name", uniqueKindString, false},
+			},
+		},
+		{
+			name:     "no elements",
+			elements: nil,
+			checks: []check{
+				{"id", uniqueKindNumber, false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newUniqueValues()
+			for _, element := range tt.elements {
+				u.add(element)
+			}
+
+			for _, c := range tt.checks {
+				if got := u.unique(c.name, c.kind); got != c.unique {
+					t.Errorf("unique(%q, %q) = %v, want %v", c.name, c.kind, got, c.unique)
+				}
+			}
+		})
+	}
+}
+
+func TestUniqueValuesInvalidate(t *testing.T) {
+	u := newUniqueValues()
+	u.add(map[string]json.Token{"id": json.Number("1")})
+	u.invalidate()
+	u.add(map[string]json.Token{"id": json.Number("2")})
+
+	if u.unique("id", uniqueKindNumber) {
+		t.Errorf("unique after invalidate = true, want false")
+	}
+}
+
+func TestUniqueValuesAddRoot(t *testing.T) {
+	tests := []struct {
+		name   string
+		root   JsonObject
+		unique bool
+	}{
+		{"array of maps", JsonArray{JsonMap{"id": json.Number("1")}, JsonMap{"id": json.Number("2")}}, true},
+		{"array with a non map", JsonArray{JsonMap{"id": json.Number("1")}, json.Number("2")}, false},
+		{"map", JsonMap{"id": json.Number("1")}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newUniqueValues()
+			u.addRoot(tt.root)
+
+			if got := u.unique("id", uniqueKindNumber); got != tt.unique {
+				t.Errorf("unique = %v, want %v", got, tt.unique)
+			}
+		})
+	}
+}
@@ -3,9 +3,11 @@ package yoitsu
 import (
 	"go/ast"
 	"go/token"
+	"strconv"
 )
 
-func ifErrNotNilStmt() ast.Stmt {
+// ifErrNotNilStmt returns if err != nil { return results..., err }
+func ifErrNotNilStmt(results ...ast.Expr) ast.Stmt {
 	return &ast.IfStmt{
 		Cond: &ast.BinaryExpr{
 			X:  ast.NewIdent("err"),
@@ -15,7 +17,7 @@ func ifErrNotNilStmt() ast.Stmt {
 		Body: &ast.BlockStmt{
 			List: []ast.Stmt{
 				&ast.ReturnStmt{
-					Results: []ast.Expr{ast.NewIdent("err")},
+					Results: append(results, ast.NewIdent("err")),
 				},
 			},
 		},
@@ -35,11 +37,16 @@ func deferStmt(x string, sel string) ast.Stmt {
 }
 
 func unmarshallStmt(x string, sel string) ast.Stmt {
+	return unmarshallWithStmt("json", x, sel)
+}
+
+// unmarshallWithStmt returns pkg.Unmarshal(data, &x.sel)
+func unmarshallWithStmt(pkg string, x string, sel string) ast.Stmt {
 	return &ast.ReturnStmt{
 		Results: []ast.Expr{
 			&ast.CallExpr{
 				Fun: &ast.SelectorExpr{
-					X:   ast.NewIdent("json"),
+					X:   ast.NewIdent(pkg),
 					Sel: ast.NewIdent("Unmarshal"),
 				},
 				Args: []ast.Expr{
@@ -56,3 +63,52 @@ func unmarshallStmt(x string, sel string) ast.Stmt {
 		},
 	}
 }
+
+func selectorExpr(x string, sel string) *ast.SelectorExpr {
+	return &ast.SelectorExpr{
+		X:   ast.NewIdent(x),
+		Sel: ast.NewIdent(sel),
+	}
+}
+
+func stringLit(s string) *ast.BasicLit {
+	return &ast.BasicLit{
+		Kind:  token.STRING,
+		Value: strconv.Quote(s),
+	}
+}
+
+// defineStmt returns lhs := rhs
+func defineStmt(lhs []string, rhs ast.Expr) ast.Stmt {
+	idents := make([]ast.Expr, len(lhs))
+	for i, l := range lhs {
+		idents[i] = ast.NewIdent(l)
+	}
+
+	return &ast.AssignStmt{
+		Lhs: idents,
+		Tok: token.DEFINE,
+		Rhs: []ast.Expr{rhs},
+	}
+}
+
+func importSpecs(paths ...string) []ast.Spec {
+	specs := make([]ast.Spec, len(paths))
+	for i, p := range paths {
+		specs[i] = &ast.ImportSpec{
+			Path: stringLit(p),
+		}
+	}
+	return specs
+}
+
+// readFileStmts returns data, err := os.ReadFile(path) and the error check
+func readFileStmts(path string) []ast.Stmt {
+	return []ast.Stmt{
+		defineStmt([]string{"data", "err"}, &ast.CallExpr{
+			Fun:  selectorExpr("os", "ReadFile"),
+			Args: []ast.Expr{stringLit(path)},
+		}),
+		ifErrNotNilStmt(),
+	}
+}
@@ -0,0 +1,302 @@
+// Command yoitsu generates go types from a json source.
+//
+// Intended to be used in go:generate lines, for example
+//
+//	//go:generate go run github.com/Fesaa/yoitsu/cmd/yoitsu -src data.json -name Data -pkg data
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Fesaa/yoitsu"
+)
+
+type options struct {
+	srcs       []string
+	schema     string
+	openApi    string
+	client     bool
+	har        string
+	harSplit   bool
+	emitSchema bool
+	name       string
+	pkg        string
+	out        string
+	accessors  bool
+	byId       bool
+	embed      bool
+	parsers    bool
+	stream     bool
+	sampling   yoitsu.Sampling
+	timeout    time.Duration
+	retries    int
+	cacheDir   string
+	optional   yoitsu.OptionalFields
+	unions     yoitsu.Unions
+}
+
+func main() {
+	var (
+		opts   options
+		src    string
+		config string
+	)
+
+	flag.StringVar(&config, "config", "", "json or yaml config file describing multiple generations, other flags are ignored")
+	flag.StringVar(&src, "src", "", "file path or url to load the json from, - reads stdin. Files ending in .yaml, .yml, .toml, .ndjson, .jsonl, .csv or .xml are read in that format")
+	flag.StringVar(&opts.schema, "schema", "", "JSON Schema file to generate the types from, -src is then optional and only used by the accessors")
+	flag.StringVar(&opts.openApi, "openapi", "", "OpenAPI 3 document to generate the component schemas from, json or yaml")
+	flag.BoolVar(&opts.client, "client", false, "generate a client for the operations of -openapi")
+	flag.StringVar(&opts.har, "har", "", "HAR file to generate a root type per endpoint from, using the json responses as samples")
+	flag.BoolVar(&opts.harSplit, "har-split", false, "generate a file per endpoint of -har, named after the endpoint")
+	flag.StringVar(&opts.name, "name", "", "name of the root type, defaults to the file name of src")
+	flag.StringVar(&opts.pkg, "pkg", "", "package name of the generated file, defaults to \"generated\"")
+	flag.StringVar(&opts.out, "out", ".", "directory to write the generated file to")
+	flag.BoolVar(&opts.accessors, "accessors", false, "generate an Accessor struct loading data from src")
+	flag.BoolVar(&opts.byId, "by-id", false, "generate ById methods on the Accessor, implies -accessors")
+	flag.BoolVar(&opts.embed, "embed", false, "load data with go:embed in the generated accessors, src must be in the output directory")
+	flag.BoolVar(&opts.emitSchema, "emit-schema", false, "also write a JSON Schema of the generated types to <name>.schema.json")
+	flag.BoolVar(&opts.parsers, "parsers", false, "detect times, dates, durations, uuids, urls and ip addresses")
+	flag.BoolVar(&opts.stream, "stream", false, "read json files token by token instead of into memory, for inputs too large to decode at once")
+	flag.IntVar(&opts.sampling.First, "sample-first", 0, "only infer from the first n elements of a root array")
+	flag.IntVar(&opts.sampling.Reservoir, "sample-reservoir", 0, "infer from a random sample of n elements of a root array, chosen with -sample-seed")
+	flag.Uint64Var(&opts.sampling.Seed, "sample-seed", 0, "seed of -sample-reservoir, the same seed always picks the same elements")
+	flag.Int64Var(&opts.sampling.Bytes, "sample-bytes", 0, "stop reading elements of a root array after n bytes, requires -stream")
+	flag.DurationVar(&opts.sampling.Duration, "sample-duration", 0, "stop reading elements of a root array after this duration, the output is then not reproducible")
+	flag.DurationVar(&opts.timeout, "timeout", 0, "timeout of a single request when src is an url")
+	flag.IntVar(&opts.retries, "retries", 0, "amount of times to retry a failed request when src is an url")
+	flag.StringVar(&opts.cacheDir, "cache", "", "directory to cache responses in when src is an url, used when offline")
+	flag.TextVar(&opts.optional, "optional", yoitsu.OptionalFieldsPlain, "how to emit fields missing or null in some samples, or not required by a schema: plain, pointer or omitempty (default pointer with -schema or -openapi)")
+	flag.TextVar(&opts.unions, "unions", yoitsu.UnionsError, "how to emit values with different types across samples: error, raw, any or struct")
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s -src <file|url> [flags] [more samples...]\n       %s -schema <file> [-src <file|url>] [flags]\n       %s -openapi <file> [-client] [flags]\n       %s -har <file> [-har-split] [flags]\n       %s -config <file>\n\n", os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	optionalSet := false
+	flag.Visit(func(f *flag.Flag) {
+		optionalSet = optionalSet || f.Name == "optional"
+	})
+	if !optionalSet && (opts.schema != "" || opts.openApi != "") {
+		// So required decides between pointer and value fields
+		opts.optional = yoitsu.OptionalFieldsPointer
+	}
+
+	if config != "" {
+		if err := yoitsu.RunConfig(config); err != nil {
+			fmt.Fprintf(os.Stderr, "yoitsu: %s\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if src == "" && opts.schema == "" && opts.openApi == "" && opts.har == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if src != "" {
+		opts.srcs = append([]string{src}, flag.Args()...)
+	}
+	opts.accessors = opts.accessors || opts.byId
+	if err := run(ctx, opts); err != nil {
+		fmt.Fprintf(os.Stderr, "yoitsu: %s\n", describe(err))
+		stop()
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, opts options) error {
+	name := opts.name
+	if name == "" && len(opts.srcs) > 0 && opts.srcs[0] == "-" {
+		return fmt.Errorf("-name is required when reading from stdin")
+	}
+	if name == "" && len(opts.srcs) > 0 {
+		name = nameFromSrc(opts.srcs[0])
+	}
+	if name == "" && opts.openApi != "" {
+		name = nameFromSrc(opts.openApi)
+	}
+	if name == "" && opts.har != "" {
+		name = nameFromSrc(opts.har)
+	}
+	if name == "" {
+		name = nameFromSrc(opts.schema)
+	}
+
+	sources := make([]yoitsu.Source, len(opts.srcs))
+	for i, src := range opts.srcs {
+		switch {
+		case src == "-":
+			sources[i] = yoitsu.NewReaderSource(name, os.Stdin)
+		case isUrl(src):
+			sources[i] = yoitsu.NewUrlSource(name, src,
+				yoitsu.UrlSourceWithTimeout(opts.timeout),
+				yoitsu.UrlSourceWithRetries(opts.retries, 500*time.Millisecond),
+				yoitsu.UrlSourceWithCache(opts.cacheDir))
+		case hasExt(src, ".yaml", ".yml"):
+			sources[i] = yoitsu.NewYamlSource(name, src)
+		case hasExt(src, ".toml"):
+			sources[i] = yoitsu.NewTomlSource(name, src)
+		case hasExt(src, ".ndjson", ".jsonl"):
+			sources[i] = yoitsu.NewNdjsonSource(name, src)
+		case hasExt(src, ".csv"):
+			sources[i] = yoitsu.NewCsvSource(name, src)
+		case hasExt(src, ".xml"):
+			sources[i] = yoitsu.NewXmlSource(name, src)
+		case opts.embed:
+			sources[i] = yoitsu.NewEmbedSource(name, src)
+		default:
+			sources[i] = yoitsu.NewFileSource(name, src)
+		}
+	}
+
+	var source yoitsu.Source
+	switch {
+	case len(sources) == 1:
+		source = sources[0]
+	case len(sources) > 1:
+		source = yoitsu.NewMultiSource(name, sources...)
+	}
+
+	if opts.openApi != "" {
+		if source != nil || opts.schema != "" {
+			return fmt.Errorf("-openapi can't be combined with -src or -schema")
+		}
+
+		if opts.client {
+			source = yoitsu.NewOpenApiSource(name, opts.openApi, yoitsu.OpenApiSourceWithClient())
+		} else {
+			source = yoitsu.NewOpenApiSource(name, opts.openApi)
+		}
+	}
+
+	if opts.har != "" {
+		if source != nil || opts.schema != "" || opts.openApi != "" {
+			return fmt.Errorf("-har can't be combined with -src, -schema or -openapi")
+		}
+		source = yoitsu.NewHarSource(name, opts.har)
+	}
+
+	if opts.schema != "" {
+		if source == nil {
+			source = yoitsu.NewJsonSchemaSource(name, opts.schema)
+		} else {
+			source = yoitsu.NewJsonSchemaSource(name, opts.schema, yoitsu.JsonSchemaSourceWithData(source))
+		}
+	}
+
+	yOpts := []yoitsu.Option[*yoitsu.Yoitsu]{
+		yoitsu.WithGenerateAccessors(func(a *yoitsu.Accessors) {
+			a.Generate = opts.accessors
+			a.ById = opts.byId
+		}),
+		yoitsu.WithOptionalFields(opts.optional),
+		yoitsu.WithUnions(opts.unions),
+	}
+	if opts.pkg != "" {
+		yOpts = append(yOpts, yoitsu.WithPackageName(opts.pkg))
+	}
+	if opts.parsers {
+		yOpts = append(yOpts, yoitsu.WithDefaultNativeParsers())
+	}
+	if opts.stream {
+		yOpts = append(yOpts, yoitsu.WithStreaming())
+	}
+	if opts.sampling != (yoitsu.Sampling{}) {
+		yOpts = append(yOpts, yoitsu.WithSampling(opts.sampling))
+	}
+
+	if opts.har != "" && opts.harSplit {
+		endpoints, err := yoitsu.ReadHarEndpoints(opts.har)
+		if err != nil {
+			return err
+		}
+
+		for _, endpoint := range endpoints {
+			err = generate(ctx, opts, endpoint.Name, yoitsu.New(endpoint.Source(), yOpts...))
+			if errors.Is(err, yoitsu.ErrNoData) {
+				// Only empty responses, like in yoitsu.NewHarSource
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("%s %s: %w", endpoint.Method, endpoint.Path, err)
+			}
+		}
+		return nil
+	}
+
+	return generate(ctx, opts, name, yoitsu.New(source, yOpts...))
+}
+
+func generate(ctx context.Context, opts options, name string, y *yoitsu.Yoitsu) error {
+	if err := y.GenerateFileContext(ctx); err != nil {
+		return err
+	}
+
+	if sample, ok := y.Sample(); ok {
+		fmt.Fprintf(os.Stderr, "yoitsu: %s was inferred from %s\n", name, sample)
+	}
+
+	if err := y.WriteToDisk(opts.out); err != nil {
+		return err
+	}
+
+	if opts.emitSchema {
+		return y.WriteJsonSchema(opts.out)
+	}
+	return nil
+}
+
+func isUrl(src string) bool {
+	return strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://")
+}
+
+func hasExt(src string, exts ...string) bool {
+	ext := strings.ToLower(filepath.Ext(src))
+	for _, e := range exts {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+func nameFromSrc(src string) string {
+	base := filepath.Base(src)
+	if isUrl(src) {
+		base = src[strings.LastIndex(src, "/")+1:]
+		if i := strings.IndexAny(base, "?#"); i != -1 {
+			base = base[:i]
+		}
+	}
+
+	base = strings.TrimSuffix(base, filepath.Ext(base))
+	if base == "" {
+		return "Root"
+	}
+	return base
+}
+
+func describe(err error) string {
+	switch {
+	case errors.Is(err, yoitsu.ErrNoData):
+		return fmt.Sprintf("the source did not contain any data to generate types from (%s)", err)
+	case errors.Is(err, yoitsu.ErrSrcIsNotLoadAble):
+		return fmt.Sprintf("accessors were requested, but the source cannot be loaded at runtime (%s)", err)
+	}
+	return err.Error()
+}
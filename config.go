@@ -0,0 +1,482 @@
+package yoitsu
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config describes multiple generations, see RunConfig.
+//
+// Relative paths are resolved against the directory the config file is in. Yaml files use the same keys
+//
+//	{
+//	  "entries": [
+//	    {
+//	      "name": "Users",
+//	      "file": "testdata/users.json",
+//	      "package": "users",
+//	      "out": "users",
+//	      "accessors": {"generate": true, "byId": true},
+//	      "parsers": ["time"]
+//	    }
+//	  ]
+//	}
+type Config struct {
+	Entries []ConfigEntry `json:"entries"`
+}
+
+// ConfigEntry describes a single generation. Exactly one of File, Files, Glob, Url, OpenApi or Har must be set,
+// unless Schema is set
+type ConfigEntry struct {
+	// Name is used as Source.Name
+	Name string `json:"name"`
+	File string `json:"file,omitempty"`
+	// Embed uses NewEmbedSource for File, the file must be in Out
+	Embed bool `json:"embed,omitempty"`
+	// Format of File, one of "json" (default), "yaml", "toml", "ndjson", "csv" or "xml"
+	Format string `json:"format,omitempty"`
+	// Files are samples of the same type, see NewMultiSource
+	Files []string `json:"files,omitempty"`
+	// Glob is passed to NewGlobSource, GlobAsArray to use GlobSourceAsArray
+	Glob        string `json:"glob,omitempty"`
+	GlobAsArray bool   `json:"globAsArray,omitempty"`
+	Url         string `json:"url,omitempty"`
+	// Schema is a JSON Schema file passed to NewJsonSchemaSource, File, Files, Glob or Url are then optional and
+	// only used by the accessors
+	Schema string `json:"schema,omitempty"`
+	// OpenApi is an OpenAPI 3 document passed to NewOpenApiSource, Client to use OpenApiSourceWithClient
+	OpenApi string `json:"openapi,omitempty"`
+	Client  bool   `json:"client,omitempty"`
+	// Har is a HAR file passed to NewHarSource, HarSplit generates a file per endpoint instead, see ReadHarEndpoints
+	Har      string `json:"har,omitempty"`
+	HarSplit bool   `json:"harSplit,omitempty"`
+	// Http configures the request made for Url
+	Http ConfigHttp `json:"http"`
+	// Package is passed to WithPackageName
+	Package string `json:"package,omitempty"`
+	// Out is the directory passed to Yoitsu.WriteToDisk, defaults to the directory of the config
+	Out       string    `json:"out,omitempty"`
+	Accessors Accessors `json:"accessors"`
+	// EmitSchema also writes Yoitsu.JsonSchema to Out, see Yoitsu.WriteJsonSchema
+	EmitSchema bool `json:"emitSchema,omitempty"`
+	// OptionalFields is passed to WithOptionalFields, one of "plain", "pointer" or "omitempty". Defaults to
+	// "pointer" if Schema or OpenApi is set, and "plain" otherwise
+	OptionalFields *OptionalFields `json:"optionalFields,omitempty"`
+	// Unions is passed to WithUnions, one of "error", "raw", "any" or "struct"
+	Unions Unions `json:"unions,omitempty"`
+	// Parsers are the names of NativeTypeParser's to register, see NamedParsers
+	Parsers []string `json:"parsers,omitempty"`
+	// DefaultParsers registers the DefaultNativeParsers before Parsers
+	DefaultParsers bool `json:"defaultParsers,omitempty"`
+	// Stream reads files token by token, see WithStreaming
+	Stream bool `json:"stream,omitempty"`
+	// Sampling is passed to WithSampling
+	Sampling ConfigSampling `json:"sampling"`
+}
+
+// ConfigSampling holds the options for WithSampling, see Sampling
+type ConfigSampling struct {
+	First     int            `json:"first,omitempty"`
+	Reservoir int            `json:"reservoir,omitempty"`
+	Seed      uint64         `json:"seed,omitempty"`
+	Bytes     int64          `json:"bytes,omitempty"`
+	Duration  ConfigDuration `json:"duration"`
+}
+
+func (c ConfigSampling) sampling() Sampling {
+	return Sampling{
+		First:     c.First,
+		Reservoir: c.Reservoir,
+		Seed:      c.Seed,
+		Bytes:     c.Bytes,
+		Duration:  c.Duration.Duration,
+	}
+}
+
+// ConfigHttp holds the options for NewUrlSource. Secrets should be passed by environment variable
+type ConfigHttp struct {
+	Method     string            `json:"method,omitempty"`
+	Body       string            `json:"body,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	EnvHeaders map[string]string `json:"envHeaders,omitempty"`
+	BearerEnv  string            `json:"bearerEnv,omitempty"`
+	// BasicAuthEnv holds the environment variables for the username and password
+	BasicAuthEnv []string `json:"basicAuthEnv,omitempty"`
+	// Timeout, Retries and RetryBackoff are passed to UrlSourceWithTimeout and UrlSourceWithRetries.
+	// Durations are strings accepted by time.ParseDuration
+	Timeout      ConfigDuration `json:"timeout"`
+	Retries      int            `json:"retries,omitempty"`
+	RetryBackoff ConfigDuration `json:"retryBackoff"`
+	// CacheDir is passed to UrlSourceWithCache, relative to the config
+	CacheDir string `json:"cacheDir,omitempty"`
+}
+
+func (h ConfigHttp) options(dir string) ([]Option[*urlSource], error) {
+	var opts []Option[*urlSource]
+
+	if h.Method != "" {
+		opts = append(opts, UrlSourceWithMethod(h.Method))
+	}
+	if h.Body != "" {
+		opts = append(opts, UrlSourceWithBody([]byte(h.Body)))
+	}
+	for _, key := range sortedKeys(h.Headers) {
+		opts = append(opts, UrlSourceWithHeader(key, h.Headers[key]))
+	}
+	for _, key := range sortedKeys(h.EnvHeaders) {
+		opts = append(opts, UrlSourceWithEnvHeader(key, h.EnvHeaders[key]))
+	}
+	if h.BearerEnv != "" {
+		opts = append(opts, UrlSourceWithBearerEnv(h.BearerEnv))
+	}
+	if len(h.BasicAuthEnv) > 0 {
+		if len(h.BasicAuthEnv) != 2 {
+			return nil, fmt.Errorf("%w: basicAuthEnv must hold two environment variables", ErrInvalidConfig)
+		}
+		opts = append(opts, UrlSourceWithBasicAuthEnv(h.BasicAuthEnv[0], h.BasicAuthEnv[1]))
+	}
+
+	if h.Timeout.Duration > 0 {
+		opts = append(opts, UrlSourceWithTimeout(h.Timeout.Duration))
+	}
+	if h.Retries > 0 {
+		opts = append(opts, UrlSourceWithRetries(h.Retries, h.RetryBackoff.Duration))
+	}
+
+	if h.CacheDir != "" {
+		opts = append(opts, UrlSourceWithCache(resolvePath(dir, h.CacheDir)))
+	}
+
+	return opts, nil
+}
+
+// ConfigDuration is a time.Duration (un)marshalled as a string, see time.ParseDuration
+type ConfigDuration struct {
+	time.Duration
+}
+
+func (d ConfigDuration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *ConfigDuration) UnmarshalText(text []byte) error {
+	dur, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	d.Duration = dur
+	return nil
+}
+
+// ConfigEntryError wraps the error returned while generating a ConfigEntry
+type ConfigEntryError struct {
+	Name string
+	Err  error
+}
+
+func (e *ConfigEntryError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Name, e.Err)
+}
+
+func (e *ConfigEntryError) Unwrap() error {
+	return e.Err
+}
+
+// LoadConfig reads a Config from a json or yaml file, depending on the extension
+func LoadConfig(path string) (*Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		var doc interface{}
+		if err = yaml.Unmarshal(b, &doc); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+		}
+
+		normalized, err := normalizeTree(doc)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+		}
+
+		if b, err = json.Marshal(normalized); err != nil {
+			return nil, err
+		}
+	}
+
+	var c Config
+	if err = json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	return &c, nil
+}
+
+// RunConfig loads the Config at path and generates all of its entries.
+// A failing entry does not stop the others, the returned error joins a ConfigEntryError per failed entry
+func RunConfig(path string) error {
+	c, err := LoadConfig(path)
+	if err != nil {
+		return err
+	}
+
+	return c.Run(filepath.Dir(path))
+}
+
+// Run generates all entries, resolving relative paths against dir. See RunConfig
+func (c *Config) Run(dir string) error {
+	var errs []error
+
+	for i, entry := range c.Entries {
+		if err := entry.run(dir); err != nil {
+			name := entry.Name
+			if name == "" {
+				name = fmt.Sprintf("entry %d", i)
+			}
+			errs = append(errs, &ConfigEntryError{Name: name, Err: err})
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (e ConfigEntry) run(dir string) error {
+	if e.Har != "" && e.HarSplit {
+		return e.runHarSplit(dir)
+	}
+
+	y, err := e.Yoitsu(dir)
+	if err != nil {
+		return err
+	}
+
+	return e.write(y, dir)
+}
+
+// runHarSplit generates a file per endpoint of the HAR file
+func (e ConfigEntry) runHarSplit(dir string) error {
+	if _, err := e.source(dir); err != nil {
+		return err
+	}
+
+	opts, err := e.options()
+	if err != nil {
+		return err
+	}
+
+	endpoints, err := ReadHarEndpoints(resolvePath(dir, e.Har))
+	if err != nil {
+		return err
+	}
+
+	for _, endpoint := range endpoints {
+		err = e.write(New(endpoint.Source(), opts...), dir)
+		if errors.Is(err, ErrNoData) {
+			// Only empty responses, like in NewHarSource
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", endpoint.Method, endpoint.Path, err)
+		}
+	}
+	return nil
+}
+
+func (e ConfigEntry) write(y *Yoitsu, dir string) error {
+	if err := y.GenerateFile(); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(resolvePath(dir, e.Out), 0o755); err != nil {
+		return err
+	}
+
+	if err := y.WriteToDisk(resolvePath(dir, e.Out)); err != nil {
+		return err
+	}
+
+	if e.EmitSchema {
+		return y.WriteJsonSchema(resolvePath(dir, e.Out))
+	}
+	return nil
+}
+
+// Yoitsu constructs the Yoitsu instance described by this entry, resolving relative paths against dir
+func (e ConfigEntry) Yoitsu(dir string) (*Yoitsu, error) {
+	src, err := e.source(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := e.options()
+	if err != nil {
+		return nil, err
+	}
+
+	return New(src, opts...), nil
+}
+
+// optionalFields returns OptionalFields, or its default
+func (e ConfigEntry) optionalFields() OptionalFields {
+	switch {
+	case e.OptionalFields != nil:
+		return *e.OptionalFields
+	case e.Schema != "" || e.OpenApi != "":
+		// So required decides between pointer and value fields
+		return OptionalFieldsPointer
+	}
+	return OptionalFieldsPlain
+}
+
+func (e ConfigEntry) options() ([]Option[*Yoitsu], error) {
+	opts := []Option[*Yoitsu]{
+		WithGenerateAccessors(func(a *Accessors) {
+			*a = e.Accessors
+		}),
+		WithOptionalFields(e.optionalFields()),
+		WithUnions(e.Unions),
+	}
+
+	if e.Package != "" {
+		opts = append(opts, WithPackageName(e.Package))
+	}
+
+	if e.DefaultParsers {
+		opts = append(opts, WithDefaultNativeParsers())
+	}
+
+	if e.Stream {
+		opts = append(opts, WithStreaming())
+	}
+
+	if sampling := e.Sampling.sampling(); sampling.enabled() {
+		opts = append(opts, WithSampling(sampling))
+	}
+
+	for _, name := range e.Parsers {
+		np, ok := NamedParsers[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown parser %q", ErrInvalidConfig, name)
+		}
+		opts = append(opts, WithNativeTypeParser(np.Parent, np.Parser))
+	}
+
+	return opts, nil
+}
+
+func (e ConfigEntry) source(dir string) (Source, error) {
+	if e.Name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidConfig)
+	}
+
+	set := 0
+	for _, ok := range []bool{e.File != "", len(e.Files) > 0, e.Glob != "", e.Url != ""} {
+		if ok {
+			set++
+		}
+	}
+
+	if e.Har != "" {
+		if set > 0 || e.Schema != "" || e.OpenApi != "" {
+			return nil, fmt.Errorf("%w: har can't be combined with other sources", ErrInvalidConfig)
+		}
+		return NewHarSource(e.Name, resolvePath(dir, e.Har)), nil
+	}
+
+	if e.OpenApi != "" {
+		if set > 0 || e.Schema != "" {
+			return nil, fmt.Errorf("%w: openapi can't be combined with other sources", ErrInvalidConfig)
+		}
+
+		var opts []Option[*openApiSource]
+		if e.Client {
+			opts = append(opts, OpenApiSourceWithClient())
+		}
+		return NewOpenApiSource(e.Name, resolvePath(dir, e.OpenApi), opts...), nil
+	}
+
+	if e.Schema != "" {
+		var opts []Option[*jsonSchemaSource]
+		if set > 1 {
+			return nil, fmt.Errorf("%w: at most one of file, files, glob and url may be set with schema", ErrInvalidConfig)
+		}
+		if set == 1 {
+			data, err := e.dataSource(dir)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, JsonSchemaSourceWithData(data))
+		}
+		return NewJsonSchemaSource(e.Name, resolvePath(dir, e.Schema), opts...), nil
+	}
+
+	if set != 1 {
+		return nil, fmt.Errorf("%w: exactly one of file, files, glob, url, openapi and har must be set", ErrInvalidConfig)
+	}
+
+	return e.dataSource(dir)
+}
+
+// dataSource returns the Source for File, Files, Glob or Url
+func (e ConfigEntry) dataSource(dir string) (Source, error) {
+	switch {
+	case e.File != "":
+		return e.fileSource(resolvePath(dir, e.File))
+	case e.Url != "":
+		opts, err := e.Http.options(dir)
+		if err != nil {
+			return nil, err
+		}
+		return NewUrlSource(e.Name, e.Url, opts...), nil
+	case e.Glob != "":
+		var opts []Option[*globSource]
+		if e.GlobAsArray {
+			opts = append(opts, GlobSourceAsArray())
+		}
+		return NewGlobSource(e.Name, resolvePath(dir, e.Glob), opts...), nil
+	}
+
+	sources := make([]Source, len(e.Files))
+	for i, f := range e.Files {
+		sources[i] = NewFileSource(e.Name, resolvePath(dir, f))
+	}
+	return NewMultiSource(e.Name, sources...), nil
+}
+
+func (e ConfigEntry) fileSource(path string) (Source, error) {
+	switch e.Format {
+	case "", "json":
+		if e.Embed {
+			return NewEmbedSource(e.Name, path), nil
+		}
+		return NewFileSource(e.Name, path), nil
+	case "yaml":
+		return NewYamlSource(e.Name, path), nil
+	case "toml":
+		return NewTomlSource(e.Name, path), nil
+	case "ndjson":
+		return NewNdjsonSource(e.Name, path), nil
+	case "csv":
+		return NewCsvSource(e.Name, path), nil
+	case "xml":
+		return NewXmlSource(e.Name, path), nil
+	}
+
+	return nil, fmt.Errorf("%w: unknown format %q", ErrInvalidConfig, e.Format)
+}
+
+func resolvePath(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
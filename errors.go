@@ -8,4 +8,8 @@ var (
 	ErrUnknownType             = errors.New("unknown type")
 	ErrCannotRegisterForType   = errors.New("cannot register for type")
 	ErrSrcIsNotLoadAble        = errors.New("src is not loadable")
+	ErrInvalidConfig           = errors.New("invalid config")
+	ErrUnexpectedStatus        = errors.New("unexpected status")
+	ErrUnsupportedSchema       = errors.New("unsupported schema")
+	ErrSamplesNotCombinable    = errors.New("samples can't be combined")
 )
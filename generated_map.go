@@ -25,6 +25,10 @@ func (m *MapType) IsComplexObject() bool {
 }
 
 func (m *MapType) Merge(other GeneratedType) (GeneratedType, error) {
+	if other.SameType(InterfaceType, false) {
+		return m, nil
+	}
+
 	mType, ok := other.(*MapType)
 	if !ok {
 		return nil, fmt.Errorf("MapType %w %T", ErrCantMergeDifferentTypes, other)
@@ -8,11 +8,23 @@ import (
 var (
 	StringType    = NewNativeType("string", "")
 	Float64Type   = NewNativeType("float64", "")
+	IntType       = NewNativeType("int", "")
+	Int64Type     = NewNativeType("int64", "")
 	BoolType      = NewNativeType("bool", "")
 	InterfaceType = NewNativeType("interface{}", "")
 	TimeType      = NewNativeType("time.Time", "time")
+
+	DateType     = NewNativeType("types.Date", typesImport)
+	UnixTimeType = NewNativeType("types.UnixTime", typesImport)
+	DurationType = NewNativeType("types.Duration", typesImport)
+	UUIDType     = NewNativeType("types.UUID", typesImport)
+	URLType      = NewNativeType("types.URL", typesImport)
+	AddrType     = NewNativeType("netip.Addr", "net/netip")
 )
 
+// typesImport is the package holding wrappers for types encoding/json can't handle directly
+const typesImport = "github.com/Fesaa/yoitsu/types"
+
 func NewNativeType(typeName string, importLine string) GeneratedType {
 	return &NativeType{
 		_type:   typeName,
@@ -26,6 +38,9 @@ func NewNativeType(typeName string, importLine string) GeneratedType {
 type NativeType struct {
 	_type   string
 	_import string
+
+	// parent is the type a NativeTypeParser specialised, merging with a different type falls back to it
+	parent *NativeType
 }
 
 func (g *NativeType) UnderLyingType() GeneratedType {
@@ -33,7 +48,36 @@ func (g *NativeType) UnderLyingType() GeneratedType {
 }
 
 func (g *NativeType) Copy() GeneratedType {
-	return NewNativeType(g._type, g._import)
+	return &NativeType{
+		_type:   g._type,
+		_import: g._import,
+		parent:  g.parent,
+	}
+}
+
+// withParent returns a copy of gType remembering it was specialised from parent, if gType is a NativeType
+func withParent(gType GeneratedType, parent GeneratedType) GeneratedType {
+	nType, ok := gType.(*NativeType)
+	if !ok || nType.SameType(parent, false) {
+		return gType
+	}
+
+	pType, ok := parent.(*NativeType)
+	if !ok {
+		return gType
+	}
+
+	cp := nType.Copy().(*NativeType)
+	cp.parent = pType
+	return cp
+}
+
+// base returns the type before a NativeTypeParser specialised it
+func (g *NativeType) base() *NativeType {
+	if g.parent != nil {
+		return g.parent
+	}
+	return g
 }
 
 func (g *NativeType) IsComplexObject() bool {
@@ -49,7 +93,8 @@ func (g *NativeType) Merge(other GeneratedType) (GeneratedType, error) {
 		return other, nil
 	}
 
-	if _, ok := other.(*NativeType); !ok {
+	nOther, ok := other.(*NativeType)
+	if !ok {
 		return nil, fmt.Errorf("nativeType %w", ErrCantMergeDifferentTypes)
 	}
 
@@ -57,15 +102,59 @@ func (g *NativeType) Merge(other GeneratedType) (GeneratedType, error) {
 		return g, nil
 	}
 
-	if !g.SameType(other, false) {
-		return nil, fmt.Errorf("NativeType %w", ErrCantMergeDifferentTypes)
+	if g.SameType(other, false) {
+		return g, nil
 	}
 
-	return g, nil
+	if isNumeric(g) && isNumeric(other) {
+		return widenNumeric(g, other), nil
+	}
+
+	// Samples disagree on the specialised type, fall back to what they were before
+	if g.parent != nil || nOther.parent != nil {
+		return g.base().Merge(nOther.base())
+	}
+
+	return nil, fmt.Errorf("NativeType %w", ErrCantMergeDifferentTypes)
 }
 
 func (g *NativeType) SameType(other GeneratedType, forgiving bool) bool {
-	return g.Type() == other.Type()
+	if !forgiving {
+		return g.Type() == other.Type()
+	}
+
+	nOther, ok := other.(*NativeType)
+	if !ok {
+		return false
+	}
+
+	if isNumeric(g.base()) && isNumeric(nOther.base()) {
+		return true
+	}
+	return g.base().Type() == nOther.base().Type()
+}
+
+// numericTypes are ordered from narrowest to widest, merging two numeric types results in the widest
+var numericTypes = []GeneratedType{IntType, Int64Type, Float64Type}
+
+func numericRank(g GeneratedType) int {
+	for i, t := range numericTypes {
+		if t.Type() == g.Type() {
+			return i
+		}
+	}
+	return -1
+}
+
+func isNumeric(g GeneratedType) bool {
+	return numericRank(g) != -1
+}
+
+func widenNumeric(a, b GeneratedType) GeneratedType {
+	if numericRank(a) >= numericRank(b) {
+		return a
+	}
+	return b
 }
 
 func (g *NativeType) Imports() []string {
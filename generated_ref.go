@@ -0,0 +1,60 @@
+package yoitsu
+
+import (
+	"fmt"
+	"go/ast"
+)
+
+// structRefType refers to a StructType by name, without its fields. It is used for a $ref within the schema it points
+// to, the StructType itself is declared by the type the $ref resolves to. Fields of this type are always pointers, as
+// a struct can't contain itself
+type structRefType struct {
+	name string
+}
+
+func (r *structRefType) Cleanup() (GeneratedType, error) {
+	return r, nil
+}
+
+// IsComplexObject returns false, the StructType is declared elsewhere
+func (r *structRefType) IsComplexObject() bool {
+	return false
+}
+
+func (r *structRefType) Merge(other GeneratedType) (GeneratedType, error) {
+	if other.SameType(InterfaceType, false) || r.SameType(other, false) {
+		return r, nil
+	}
+	return nil, fmt.Errorf("structRefType %w %T", ErrCantMergeDifferentTypes, other)
+}
+
+func (r *structRefType) Type() string {
+	return r.name
+}
+
+func (r *structRefType) UnderLyingType() GeneratedType {
+	return r
+}
+
+// SameType returns true for a reference to, or the StructType with the same name
+func (r *structRefType) SameType(other GeneratedType, forgiving bool) bool {
+	switch o := other.(type) {
+	case *structRefType:
+		return o.name == r.name
+	case *StructType:
+		return o.Type() == r.name
+	}
+	return false
+}
+
+func (r *structRefType) Imports() []string {
+	return nil
+}
+
+func (r *structRefType) Representation() []ast.Decl {
+	return nil
+}
+
+func (r *structRefType) Copy() GeneratedType {
+	return &structRefType{name: r.name}
+}
@@ -0,0 +1,102 @@
+package yoitsu
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"slices"
+)
+
+// typeSet groups several named root types emitted in the same file, e.g. the component schemas of an OpenAPI
+// document. A type whose name differs from its entry, like a NativeType, is emitted as an alias
+type typeSet struct {
+	names []string
+	types map[string]GeneratedType
+}
+
+func newTypeSet() *typeSet {
+	return &typeSet{
+		types: make(map[string]GeneratedType),
+	}
+}
+
+// add registers the type under name, replacing any type added under the same name before
+func (t *typeSet) add(name string, gType GeneratedType) {
+	if _, ok := t.types[name]; !ok {
+		t.names = append(t.names, name)
+	}
+	t.types[name] = gType
+}
+
+func (t *typeSet) Cleanup() (GeneratedType, error) {
+	return t, nil
+}
+
+func (t *typeSet) IsComplexObject() bool {
+	return true
+}
+
+func (t *typeSet) Merge(other GeneratedType) (GeneratedType, error) {
+	return nil, fmt.Errorf("typeSet %w", ErrCantMergeDifferentTypes)
+}
+
+func (t *typeSet) Type() string {
+	return ""
+}
+
+func (t *typeSet) UnderLyingType() GeneratedType {
+	return t
+}
+
+func (t *typeSet) SameType(other GeneratedType, forgiving bool) bool {
+	return false
+}
+
+func (t *typeSet) Imports() []string {
+	var imports []string
+	for _, name := range t.names {
+		for _, i := range t.types[name].Imports() {
+			if !slices.Contains(imports, i) {
+				imports = append(imports, i)
+			}
+		}
+	}
+	return imports
+}
+
+func (t *typeSet) Representation() []ast.Decl {
+	var decls []ast.Decl
+
+	for _, name := range t.names {
+		gType := t.types[name]
+
+		if gType.IsComplexObject() {
+			decls = append(decls, gType.Representation()...)
+		}
+
+		if gType.Type() == name {
+			continue
+		}
+
+		decls = append(decls, &ast.GenDecl{
+			Tok: token.TYPE,
+			Specs: []ast.Spec{
+				&ast.TypeSpec{
+					Name:   ast.NewIdent(name),
+					Assign: 1,
+					Type:   ast.NewIdent(gType.Type()),
+				},
+			},
+		})
+	}
+
+	return decls
+}
+
+func (t *typeSet) Copy() GeneratedType {
+	cp := newTypeSet()
+	for _, name := range t.names {
+		cp.add(name, t.types[name].Copy())
+	}
+	return cp
+}
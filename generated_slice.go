@@ -33,6 +33,10 @@ func (s *SliceType) IsComplexObject() bool {
 }
 
 func (s *SliceType) Merge(other GeneratedType) (GeneratedType, error) {
+	if other.SameType(InterfaceType, false) {
+		return s, nil
+	}
+
 	otherSlice, ok := other.(*SliceType)
 	if !ok {
 		return nil, fmt.Errorf("SliceType %w", ErrCantMergeDifferentTypes)
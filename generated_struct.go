@@ -29,14 +29,67 @@ type StructType struct {
 	Name   string
 	Import string
 	Fields map[string]*StructField
-
-	tag string
+	// Samples is the amount of JsonMap's merged into this StructType
+	Samples int
+
+	tag      string
+	optional OptionalFields
+	unions   Unions
+	// tagKeys are the struct tag keys emitted for every field, defaults to json
+	tagKeys []string
 }
 
 // StructField represents a field in a StructType
 type StructField struct {
 	Type GeneratedType
 	Tag  string
+	// Present is the amount of samples the field was present in
+	Present int
+	// Null is the amount of samples the field was null in
+	Null int
+}
+
+// Optional returns true if the field was missing, or null in at least one of the samples of the StructType
+func (f *StructField) Optional(s *StructType) bool {
+	return f.Present < s.Samples || f.Null > 0
+}
+
+// OptionalFields decides how StructType.Representation emits fields which are StructField.Optional
+type OptionalFields int
+
+const (
+	// OptionalFieldsPlain emits optional fields like any other field
+	OptionalFieldsPlain OptionalFields = iota
+	// OptionalFieldsPointer emits optional fields as pointers with omitempty. Slices, maps, interface{}'s and
+	// json.RawMessage's are not made pointers, as they can already be nil
+	OptionalFieldsPointer
+	// OptionalFieldsOmitEmpty emits optional fields with omitempty
+	OptionalFieldsOmitEmpty
+)
+
+var optionalFieldsNames = map[OptionalFields]string{
+	OptionalFieldsPlain:     "plain",
+	OptionalFieldsPointer:   "pointer",
+	OptionalFieldsOmitEmpty: "omitempty",
+}
+
+func (o OptionalFields) MarshalText() ([]byte, error) {
+	name, ok := optionalFieldsNames[o]
+	if !ok {
+		return nil, fmt.Errorf("unknown OptionalFields %d", o)
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText accepts "plain", "pointer" and "omitempty"
+func (o *OptionalFields) UnmarshalText(text []byte) error {
+	for k, v := range optionalFieldsNames {
+		if v == string(text) {
+			*o = k
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown OptionalFields %q", text)
 }
 
 func (s *StructType) UnderLyingType() GeneratedType {
@@ -47,16 +100,22 @@ func (s *StructType) Copy() GeneratedType {
 	fields := make(map[string]*StructField)
 	for k, v := range s.Fields {
 		fields[k] = &StructField{
-			Type: v.Type.Copy(),
-			Tag:  v.Tag,
+			Type:    v.Type.Copy(),
+			Tag:     v.Tag,
+			Present: v.Present,
+			Null:    v.Null,
 		}
 	}
 
 	return &StructType{
-		Name:   s.Name,
-		tag:    s.tag,
-		Import: s.Import,
-		Fields: fields,
+		Name:     s.Name,
+		tag:      s.tag,
+		Import:   s.Import,
+		Fields:   fields,
+		Samples:  s.Samples,
+		optional: s.optional,
+		unions:   s.unions,
+		tagKeys:  s.tagKeys,
 	}
 }
 
@@ -81,14 +140,17 @@ func (s *StructType) Merge(other GeneratedType) (GeneratedType, error) {
 			continue
 		}
 
-		newFieldType, err := existingField.Type.Merge(field.Type)
+		newFieldType, err := mergeTypes(s.unions, s.Name+toSafeGoName(tag), existingField.Type, field.Type)
 		if err != nil {
 			return nil, err
 		}
 
 		existingField.Type = newFieldType
+		existingField.Present += field.Present
+		existingField.Null += field.Null
 		s.Fields[tag] = existingField
 	}
+	s.Samples += st.Samples
 
 	if st.Import == "" && s.Import != "" { // Reset import and take non-imported name if merged is a new type
 		s.Name = st.Name
@@ -281,13 +343,14 @@ func (s *StructType) Representation() []ast.Decl {
 
 	for _, tag := range tags {
 		field := s.Fields[tag]
+		fieldType, tagValue := s.fieldTypeAndTag(field)
 
 		fieldList.List = append(fieldList.List, &ast.Field{
-			Names: []*ast.Ident{ast.NewIdent(toSafeGoName(field.Tag))},
-			Type:  ast.NewIdent(field.Type.Type()),
+			Names: []*ast.Ident{ast.NewIdent(s.fieldName(field.Tag))},
+			Type:  ast.NewIdent(fieldType),
 			Tag: &ast.BasicLit{
 				Kind:  token.STRING,
-				Value: fmt.Sprintf("`json:\"%s\"`", field.Tag),
+				Value: s.structTag(tagValue),
 			},
 		})
 
@@ -299,6 +362,67 @@ func (s *StructType) Representation() []ast.Decl {
 	return newTypes
 }
 
+// fieldName returns the go name of the field. For xml, the prefixes of attributes and character data are dropped
+// unless that results in a duplicate name
+func (s *StructType) fieldName(tag string) string {
+	if !slices.Contains(s.tagKeys, "xml") {
+		return toSafeGoName(tag)
+	}
+
+	trimmed := strings.TrimPrefix(strings.TrimPrefix(tag, xmlAttrPrefix), xmlTextPrefix)
+	if _, ok := s.Fields[trimmed]; ok || trimmed == "" {
+		return toSafeGoName(tag)
+	}
+	return toSafeGoName(trimmed)
+}
+
+// structTag returns the struct tag literal, with the value for every key in tagKeys
+func (s *StructType) structTag(value string) string {
+	keys := s.tagKeys
+	if len(keys) == 0 {
+		keys = []string{"json"}
+	}
+
+	tags := make([]string, len(keys))
+	for i, key := range keys {
+		keyValue := value
+		if key == "xml" {
+			keyValue = xmlTagValue(value)
+		}
+		tags[i] = fmt.Sprintf("%s:\"%s\"", key, keyValue)
+	}
+
+	return "`" + strings.Join(tags, " ") + "`"
+}
+
+// fieldTypeAndTag returns the type and json tag to use for the field, taking OptionalFields into account
+func (s *StructType) fieldTypeAndTag(field *StructField) (string, string) {
+	fieldType := field.Type.Type()
+	if _, ok := field.Type.(*structRefType); ok {
+		fieldType = tokenPointer + fieldType
+	}
+
+	if s.optional == OptionalFieldsPlain || !field.Optional(s) {
+		return fieldType, field.Tag
+	}
+
+	if s.optional == OptionalFieldsOmitEmpty || !canBePointer(field.Type) {
+		return fieldType, field.Tag + ",omitempty"
+	}
+
+	return tokenPointer + fieldType, field.Tag + ",omitempty"
+}
+
+func canBePointer(gType GeneratedType) bool {
+	switch t := gType.(type) {
+	case *SliceType, *MapType, *structRefType:
+		return false
+	case *UnionType:
+		return t.mode == UnionsStruct
+	}
+	return !gType.SameType(InterfaceType, false) && !gType.SameType(RawMessageType, false)
+}
+
 func defaultShouldConvertToMapFunc(s *StructType, allComplex, allIds bool, tracker GeneratedType) bool {
 	if !allComplex && !allIds {
 		return false
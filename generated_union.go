@@ -0,0 +1,515 @@
+package yoitsu
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"slices"
+	"strconv"
+)
+
+// RawMessageType is used for values which can't be typed more precisely
+var RawMessageType = NewNativeType("json.RawMessage", "encoding/json")
+
+// Unions decides what happens when samples of a value have a different type, e.g. a field being a number in
+// one sample and a string in another
+type Unions int
+
+const (
+	// UnionsError returns ErrCantMergeDifferentTypes
+	UnionsError Unions = iota
+	// UnionsRawMessage emits the value as json.RawMessage
+	UnionsRawMessage
+	// UnionsAny emits the value as any
+	UnionsAny
+	// UnionsStruct emits a struct with a field per json kind, and an UnmarshalJSON dispatching on the kind. Values
+	// whose json kind isn't known, e.g. time.Time or json.RawMessage, are emitted as json.RawMessage instead
+	UnionsStruct
+)
+
+var unionsNames = map[Unions]string{
+	UnionsError:      "error",
+	UnionsRawMessage: "raw",
+	UnionsAny:        "any",
+	UnionsStruct:     "struct",
+}
+
+func (u Unions) MarshalText() ([]byte, error) {
+	name, ok := unionsNames[u]
+	if !ok {
+		return nil, fmt.Errorf("unknown Unions %d", u)
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText accepts "error", "raw", "any" and "struct"
+func (u *Unions) UnmarshalText(text []byte) error {
+	for k, v := range unionsNames {
+		if v == string(text) {
+			*u = k
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown Unions %q", text)
+}
+
+// The json kinds a UnionType dispatches on, in the order they're emitted
+const (
+	kindString = "String"
+	kindNumber = "Number"
+	kindBool   = "Bool"
+	kindArray  = "Array"
+	kindObject = "Object"
+)
+
+var unionKinds = []string{kindString, kindNumber, kindBool, kindArray, kindObject}
+
+// UnionType represents a value which has different types across samples. It holds at most one variant per
+// json kind, variants of the same kind which can't be merged become RawMessageType
+//
+// See Unions for how it is represented
+type UnionType struct {
+	Name     string
+	Variants map[string]GeneratedType
+
+	mode Unions
+}
+
+// mergeTypes merges b into a, if they can't be merged and mode allows it, a UnionType is returned instead
+func mergeTypes(mode Unions, name string, a, b GeneratedType) (GeneratedType, error) {
+	if u, ok := b.(*UnionType); ok {
+		if _, ok = a.(*UnionType); !ok {
+			return u.Merge(a)
+		}
+	}
+
+	merged, err := a.Merge(b)
+	if err == nil || mode == UnionsError || !errors.Is(err, ErrCantMergeDifferentTypes) {
+		return merged, err
+	}
+
+	u := &UnionType{
+		Name:     toSafeGoName(name) + "Union",
+		Variants: make(map[string]GeneratedType),
+		mode:     mode,
+	}
+	u.add(a)
+	u.add(b)
+	return u, nil
+}
+
+func jsonKind(gType GeneratedType) string {
+	switch t := gType.(type) {
+	case *SliceType:
+		return kindArray
+	case *StructType, *MapType:
+		return kindObject
+	case *NativeType:
+		base := t.base()
+		switch {
+		case isNumeric(base):
+			return kindNumber
+		case base.SameType(StringType, false):
+			return kindString
+		case base.SameType(BoolType, false):
+			return kindBool
+		}
+	}
+	return ""
+}
+
+func (u *UnionType) add(gType GeneratedType) {
+	if other, ok := gType.(*UnionType); ok {
+		for kind, v := range other.Variants {
+			u.addKind(kind, v)
+		}
+		if other.mode == UnionsRawMessage {
+			u.widen()
+		}
+		return
+	}
+
+	if gType.SameType(InterfaceType, false) {
+		return
+	}
+
+	kind := jsonKind(gType)
+	if kind == "" {
+		// The generated UnmarshalJSON can't tell which json kind the variant is, e.g. time.Time
+		u.widen()
+		return
+	}
+	u.addKind(kind, gType)
+}
+
+// widen emits a UnionsStruct union as json.RawMessage, as one of its variants has no json kind
+func (u *UnionType) widen() {
+	if u.mode == UnionsStruct {
+		u.mode = UnionsRawMessage
+	}
+}
+
+func (u *UnionType) addKind(kind string, gType GeneratedType) {
+	existing, ok := u.Variants[kind]
+	if !ok {
+		u.Variants[kind] = gType
+		return
+	}
+
+	merged, err := existing.Merge(gType)
+	if err != nil {
+		merged = RawMessageType
+	}
+	u.Variants[kind] = merged
+}
+
+func (u *UnionType) sortedKinds() []string {
+	var kinds []string
+	for _, kind := range unionKinds {
+		if _, ok := u.Variants[kind]; ok {
+			kinds = append(kinds, kind)
+		}
+	}
+	return kinds
+}
+
+func (u *UnionType) Cleanup() (GeneratedType, error) {
+	for kind, v := range u.Variants {
+		if _, ok := v.(*MapType); ok {
+			continue
+		}
+
+		cleaned, err := v.Cleanup()
+		if err != nil {
+			return nil, err
+		}
+		u.Variants[kind] = cleaned
+	}
+	return u, nil
+}
+
+func (u *UnionType) IsComplexObject() bool {
+	return u.mode == UnionsStruct
+}
+
+func (u *UnionType) Merge(other GeneratedType) (GeneratedType, error) {
+	if other.SameType(InterfaceType, false) {
+		return u, nil
+	}
+
+	u.add(other)
+	return u, nil
+}
+
+func (u *UnionType) Type() string {
+	switch u.mode {
+	case UnionsAny:
+		return "any"
+	case UnionsStruct:
+		return u.Name
+	}
+	return RawMessageType.Type()
+}
+
+func (u *UnionType) UnderLyingType() GeneratedType {
+	return u
+}
+
+func (u *UnionType) SameType(other GeneratedType, forgiving bool) bool {
+	uOther, ok := other.(*UnionType)
+	if !ok || len(u.Variants) != len(uOther.Variants) {
+		return false
+	}
+
+	for kind, v := range u.Variants {
+		ov, ok := uOther.Variants[kind]
+		if !ok || !v.SameType(ov, forgiving) {
+			return false
+		}
+	}
+	return true
+}
+
+func (u *UnionType) Imports() []string {
+	switch u.mode {
+	case UnionsAny:
+		return nil
+	case UnionsRawMessage:
+		return RawMessageType.Imports()
+	}
+
+	imports := []string{"encoding/json", "fmt"}
+	for _, v := range u.Variants {
+		for _, i := range v.Imports() {
+			if !slices.Contains(imports, i) {
+				imports = append(imports, i)
+			}
+		}
+	}
+	return imports
+}
+
+func (u *UnionType) Copy() GeneratedType {
+	variants := make(map[string]GeneratedType, len(u.Variants))
+	for kind, v := range u.Variants {
+		variants[kind] = v.Copy()
+	}
+
+	return &UnionType{
+		Name:     u.Name,
+		Variants: variants,
+		mode:     u.mode,
+	}
+}
+
+func (u *UnionType) Representation() []ast.Decl {
+	if u.mode != UnionsStruct {
+		return nil
+	}
+
+	kinds := u.sortedKinds()
+	fieldList := ast.FieldList{}
+
+	for _, kind := range kinds {
+		v := u.Variants[kind]
+		fieldType := v.Type()
+		if canBePointer(v) {
+			fieldType = tokenPointer + fieldType
+		}
+
+		fieldList.List = append(fieldList.List, &ast.Field{
+			Names: []*ast.Ident{ast.NewIdent(kind)},
+			Type:  ast.NewIdent(fieldType),
+		})
+	}
+
+	decls := []ast.Decl{
+		&ast.GenDecl{
+			Tok: token.TYPE,
+			Specs: []ast.Spec{
+				&ast.TypeSpec{
+					Name: ast.NewIdent(u.Name),
+					Type: &ast.StructType{
+						Fields: &fieldList,
+					},
+				},
+			},
+		},
+		u.unmarshalMethod(kinds),
+		u.marshalMethod(kinds),
+	}
+
+	for _, kind := range kinds {
+		if v := u.Variants[kind]; v.IsComplexObject() {
+			decls = append(decls, v.Representation()...)
+		}
+	}
+
+	return decls
+}
+
+// unmarshalMethod dispatches on the first byte of the json value
+func (u *UnionType) unmarshalMethod(kinds []string) ast.Decl {
+	var clauses []ast.Stmt
+
+	for _, kind := range kinds {
+		var firstBytes []string
+		switch kind {
+		case kindString:
+			firstBytes = []string{`"`}
+		case kindNumber:
+			firstBytes = []string{"-", "0", "1", "2", "3", "4", "5", "6", "7", "8", "9"}
+		case kindBool:
+			firstBytes = []string{"t", "f"}
+		case kindArray:
+			firstBytes = []string{"["}
+		case kindObject:
+			firstBytes = []string{"{"}
+		}
+
+		var list []ast.Expr
+		for _, b := range firstBytes {
+			list = append(list, &ast.BasicLit{Kind: token.CHAR, Value: strconv.QuoteRune(rune(b[0]))})
+		}
+
+		clauses = append(clauses, &ast.CaseClause{
+			List: list,
+			Body: []ast.Stmt{
+				unmarshallStmt(tokenReceiver, kind),
+			},
+		})
+	}
+
+	clauses = append(clauses, &ast.CaseClause{
+		List: []ast.Expr{&ast.BasicLit{Kind: token.CHAR, Value: "'n'"}},
+		Body: []ast.Stmt{
+			&ast.ReturnStmt{Results: []ast.Expr{ast.NewIdent("nil")}},
+		},
+	})
+
+	return &ast.FuncDecl{
+		Doc: &ast.CommentGroup{
+			List: []*ast.Comment{
+				{
+					Text: fmt.Sprintf("\n// UnmarshalJSON sets the field of %s matching the kind of the json value", u.Name),
+				},
+			},
+		},
+		Recv: &ast.FieldList{
+			List: []*ast.Field{
+				{
+					Names: []*ast.Ident{ast.NewIdent(tokenReceiver)},
+					Type:  ast.NewIdent(tokenPointer + u.Name),
+				},
+			},
+		},
+		Name: ast.NewIdent("UnmarshalJSON"),
+		Type: &ast.FuncType{
+			Params: &ast.FieldList{
+				List: []*ast.Field{
+					{
+						Names: []*ast.Ident{ast.NewIdent("data")},
+						Type:  ast.NewIdent("[]byte"),
+					},
+				},
+			},
+			Results: &ast.FieldList{
+				List: []*ast.Field{
+					{
+						Type: ast.NewIdent(tokenError),
+					},
+				},
+			},
+		},
+		Body: &ast.BlockStmt{
+			List: []ast.Stmt{
+				&ast.AssignStmt{
+					Lhs: []ast.Expr{&ast.StarExpr{X: ast.NewIdent(tokenReceiver)}},
+					Tok: token.ASSIGN,
+					Rhs: []ast.Expr{&ast.CompositeLit{Type: ast.NewIdent(u.Name)}},
+				},
+				&ast.IfStmt{
+					Cond: &ast.BinaryExpr{
+						X: &ast.CallExpr{
+							Fun:  ast.NewIdent("len"),
+							Args: []ast.Expr{ast.NewIdent("data")},
+						},
+						Op: token.EQL,
+						Y:  &ast.BasicLit{Kind: token.INT, Value: "0"},
+					},
+					Body: &ast.BlockStmt{
+						List: []ast.Stmt{
+							&ast.ReturnStmt{Results: []ast.Expr{ast.NewIdent("nil")}},
+						},
+					},
+				},
+				&ast.SwitchStmt{
+					Tag: &ast.IndexExpr{
+						X:     ast.NewIdent("data"),
+						Index: &ast.BasicLit{Kind: token.INT, Value: "0"},
+					},
+					Body: &ast.BlockStmt{List: clauses},
+				},
+				&ast.ReturnStmt{
+					Results: []ast.Expr{
+						&ast.CallExpr{
+							Fun: &ast.SelectorExpr{
+								X:   ast.NewIdent("fmt"),
+								Sel: ast.NewIdent("Errorf"),
+							},
+							Args: []ast.Expr{
+								&ast.BasicLit{
+									Kind:  token.STRING,
+									Value: strconv.Quote(u.Name + ": unexpected json %s"),
+								},
+								ast.NewIdent("data"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// marshalMethod marshals the first non-nil field
+func (u *UnionType) marshalMethod(kinds []string) ast.Decl {
+	var stmts []ast.Stmt
+
+	for _, kind := range kinds {
+		stmts = append(stmts, &ast.IfStmt{
+			Cond: &ast.BinaryExpr{
+				X: &ast.SelectorExpr{
+					X:   ast.NewIdent(tokenReceiver),
+					Sel: ast.NewIdent(kind),
+				},
+				Op: token.NEQ,
+				Y:  ast.NewIdent("nil"),
+			},
+			Body: &ast.BlockStmt{
+				List: []ast.Stmt{
+					&ast.ReturnStmt{
+						Results: []ast.Expr{
+							&ast.CallExpr{
+								Fun: &ast.SelectorExpr{
+									X:   ast.NewIdent("json"),
+									Sel: ast.NewIdent("Marshal"),
+								},
+								Args: []ast.Expr{
+									&ast.SelectorExpr{
+										X:   ast.NewIdent(tokenReceiver),
+										Sel: ast.NewIdent(kind),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		})
+	}
+
+	stmts = append(stmts, &ast.ReturnStmt{
+		Results: []ast.Expr{
+			&ast.CallExpr{
+				Fun:  ast.NewIdent("[]byte"),
+				Args: []ast.Expr{&ast.BasicLit{Kind: token.STRING, Value: `"null"`}},
+			},
+			ast.NewIdent("nil"),
+		},
+	})
+
+	return &ast.FuncDecl{
+		Doc: &ast.CommentGroup{
+			List: []*ast.Comment{
+				{
+					Text: fmt.Sprintf("\n// MarshalJSON marshals the set field of %s, or null", u.Name),
+				},
+			},
+		},
+		Recv: &ast.FieldList{
+			List: []*ast.Field{
+				{
+					Names: []*ast.Ident{ast.NewIdent(tokenReceiver)},
+					Type:  ast.NewIdent(u.Name),
+				},
+			},
+		},
+		Name: ast.NewIdent("MarshalJSON"),
+		Type: &ast.FuncType{
+			Params: &ast.FieldList{},
+			Results: &ast.FieldList{
+				List: []*ast.Field{
+					{
+						Type: ast.NewIdent("[]byte"),
+					},
+					{
+						Type: ast.NewIdent(tokenError),
+					},
+				},
+			},
+		},
+		Body: &ast.BlockStmt{List: stmts},
+	}
+}
@@ -0,0 +1,127 @@
+package yoitsu
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUnionWiden(t *testing.T) {
+	tests := []struct {
+		name string
+		y    func(t *testing.T) *Yoitsu
+		want map[string]string
+	}{
+		{
+			name: "datetime and string",
+			y: func(t *testing.T) *Yoitsu {
+				f := writeTemp(t, "root.toml", "[[a]]\nv = 1979-05-27T07:32:00Z\n[[a]]\nv = \"x\"\n")
+				return New(NewTomlSource("Root", f), WithUnions(UnionsStruct))
+			},
+			want: map[string]string{
+				"RootaItem": "type RootaItem struct { V json.RawMessage `json:\"v\" toml:\"v\"` }",
+			},
+		},
+		{
+			name: "string and recursive $ref",
+			y: func(t *testing.T) *Yoitsu {
+				f := writeTemp(t, "root.schema.json", `{
+					"$defs": {
+						"Node": {
+							"type": "object",
+							"required": ["next"],
+							"properties": {"next": {"oneOf": [{"type": "string"}, {"$ref": "#/$defs/Node"}]}}
+						}
+					},
+					"$ref": "#/$defs/Node"
+				}`)
+				return New(NewJsonSchemaSource("Root", f), WithUnions(UnionsStruct))
+			},
+			want: map[string]string{
+				"Node": "type Node struct { Next json.RawMessage `json:\"next\"` }",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkDecls(t, generateDecls(t, tt.y(t)), tt.want)
+		})
+	}
+}
+
+func TestUnions(t *testing.T) {
+	const samples = `[{"v": 1, "w": 1}, {"v": "a", "w": {"x": 1}}, {"v": [true], "w": null}]`
+
+	tests := []struct {
+		name   string
+		unions Unions
+		want   map[string]string
+	}{
+		{
+			name:   "raw",
+			unions: UnionsRawMessage,
+			want: map[string]string{
+				"RootItem": "type RootItem struct { V json.RawMessage `json:\"v\"` W json.RawMessage `json:\"w\"` }",
+			},
+		},
+		{
+			name:   "any",
+			unions: UnionsAny,
+			want: map[string]string{
+				"RootItem": "type RootItem struct { V any `json:\"v\"` W any `json:\"w\"` }",
+			},
+		},
+		{
+			name:   "struct",
+			unions: UnionsStruct,
+			want: map[string]string{
+				"RootItem":       "type RootItem struct { V RootItemVUnion `json:\"v\"` W RootItemWUnion `json:\"w\"` }",
+				"RootItemVUnion": "type RootItemVUnion struct { String *string Number *int Array []bool }",
+				"RootItemWUnion": "type RootItemWUnion struct { Number *int Object *RootItemw }",
+				"RootItemw":      "type RootItemw struct { X int `json:\"x\"` }",
+				"RootItemVUnion.UnmarshalJSON": "func (a *RootItemVUnion) UnmarshalJSON(data []byte) error { " +
+					"*a = RootItemVUnion{} if len(data) == 0 { return nil } switch data[0] { " +
+					"case '\"': return json.Unmarshal(data, &a.String) " +
+					"case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9': return json.Unmarshal(data, &a.Number) " +
+					"case '[': return json.Unmarshal(data, &a.Array) " +
+					"case 'n': return nil } " +
+					"return fmt.Errorf(\"RootItemVUnion: unexpected json %s\", data) }",
+				"RootItemVUnion.MarshalJSON": "func (a RootItemVUnion) MarshalJSON() ([]byte, error) { " +
+					"if a.String != nil { return json.Marshal(a.String) } " +
+					"if a.Number != nil { return json.Marshal(a.Number) } " +
+					"if a.Array != nil { return json.Marshal(a.Array) } " +
+					"return []byte(\"null\"), nil }",
+				"RootItemWUnion.UnmarshalJSON": "func (a *RootItemWUnion) UnmarshalJSON(data []byte) error { " +
+					"*a = RootItemWUnion{} if len(data) == 0 { return nil } switch data[0] { " +
+					"case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9': return json.Unmarshal(data, &a.Number) " +
+					"case '{': return json.Unmarshal(data, &a.Object) " +
+					"case 'n': return nil } " +
+					"return fmt.Errorf(\"RootItemWUnion: unexpected json %s\", data) }",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			y := New(NewBytesSource("Root", []byte(samples)), WithUnions(tt.unions))
+			checkDecls(t, generateDecls(t, y), tt.want)
+		})
+	}
+}
+
+func TestUnionsError(t *testing.T) {
+	y := New(NewBytesSource("Root", []byte(`[{"v": 1}, {"v": "a"}]`)))
+	if err := y.GenerateFile(); !errors.Is(err, ErrCantMergeDifferentTypes) {
+		t.Errorf("GenerateFile: got %v, want ErrCantMergeDifferentTypes", err)
+	}
+}
+
+func TestUnionsMergeVariants(t *testing.T) {
+	// Objects of different samples are merged into one variant, arrays whose elements can't be merged become
+	// json.RawMessage
+	y := New(NewBytesSource("Root", []byte(`[{"v": {"a": 1}}, {"v": {"b": "x"}}, {"v": "s"}, {"v": [1]}, {"v": ["s"]}]`)), WithUnions(UnionsStruct))
+	checkDecls(t, generateDecls(t, y), map[string]string{
+		"RootItemVUnion": "type RootItemVUnion struct { String *string Array json.RawMessage Object *RootItemv }",
+		"RootItemv":      "type RootItemv struct { A int `json:\"a\"` B string `json:\"b\"` }",
+	})
+}
@@ -1,6 +1,11 @@
 package yoitsu
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+)
 
 type (
 	JsonObject = interface{}
@@ -22,7 +27,8 @@ func NewParser(yoitsu *Yoitsu) *Parser {
 	}
 }
 
-// RegisterNativeType registers a new NativeType to be used when parsing a string or float64
+// RegisterNativeType registers a new NativeType to be used when parsing a string or float64.
+// If the samples of a field disagree on the NativeType, the field falls back to parent
 func (p *Parser) RegisterNativeType(parent GeneratedType, f NativeTypeParser) error {
 	switch parent.Type() {
 	case StringType.Type():
@@ -39,16 +45,56 @@ func (p *Parser) RegisterNativeType(parent GeneratedType, f NativeTypeParser) er
 
 // ParseRoot calls Parse and then GeneratedType.Cleanup
 func (p *Parser) ParseRoot(name string, root JsonObject) (GeneratedType, error) {
-	if root == nil {
-		return nil, ErrNoData
+	return p.ParseRoots(name, root)
+}
+
+// ParseRoots calls Parse on every root, merges them as samples of the same type and then calls
+// GeneratedType.Cleanup. Nil roots are skipped
+func (p *Parser) ParseRoots(name string, roots ...JsonObject) (GeneratedType, error) {
+	var gType GeneratedType
+
+	for _, root := range roots {
+		if root == nil {
+			continue
+		}
+
+		rootType, err := p.parseRoot(name, root)
+		if err != nil {
+			return nil, err
+		}
+
+		if gType, err = p.mergeSample(name, gType, rootType); err != nil {
+			return nil, err
+		}
 	}
 
-	gType, err := p.Parse(name, root)
-	if err != nil {
-		return nil, err
+	return p.cleanupRoot(gType)
+}
+
+// parseRoot is Parse for the root of a sample, root JsonArray's are sampled, see Sampling
+func (p *Parser) parseRoot(name string, root JsonObject) (GeneratedType, error) {
+	if array, ok := root.(JsonArray); ok && p.yoitsu.sampling.enabled() {
+		return p.parseSampledArray(name, array)
 	}
+	return p.Parse(name, root)
+}
 
-	gType, err = gType.Cleanup()
+// mergeSample merges the type of a sample, a root or an element of an array, into the types of the previous samples.
+// gType is nil for the first sample
+func (p *Parser) mergeSample(name string, gType GeneratedType, sample GeneratedType) (GeneratedType, error) {
+	if gType == nil {
+		return sample, nil
+	}
+	return mergeTypes(p.yoitsu.unions, name, gType, sample)
+}
+
+// cleanupRoot calls GeneratedType.Cleanup on the merged samples, ErrNoData if there were none
+func (p *Parser) cleanupRoot(gType GeneratedType) (GeneratedType, error) {
+	if gType == nil {
+		return nil, ErrNoData
+	}
+
+	gType, err := gType.Cleanup()
 	if err != nil {
 		return nil, err
 	}
@@ -75,28 +121,33 @@ func (p *Parser) Parse(name string, s JsonObject) (GeneratedType, error) {
 func (p *Parser) ParseArray(name string, array JsonArray) (GeneratedType, error) {
 	var arrayType GeneratedType
 
-	if len(array) == 0 {
-		return &SliceType{InterfaceType}, nil
-	}
-
 	for _, v := range array {
-		gType, err := p.Parse(SliceNameFormatter(name), v)
-		if err != nil {
+		var err error
+		if arrayType, err = p.parseElement(name, arrayType, v); err != nil {
 			return nil, err
 		}
+	}
 
-		if arrayType == nil {
-			arrayType = gType
-			continue
-		}
+	return p.sliceOf(arrayType), nil
+}
 
-		arrayType, err = arrayType.Merge(gType)
-		if err != nil {
-			return nil, err
-		}
+// parseElement parses an element of the array, and merges it into the types of the previous elements
+func (p *Parser) parseElement(name string, arrayType GeneratedType, v JsonObject) (GeneratedType, error) {
+	gType, err := p.Parse(SliceNameFormatter(name), v)
+	if err != nil {
+		return nil, err
 	}
 
-	return &SliceType{p.yoitsu.universe.FindType(arrayType)}, nil
+	return p.mergeSample(SliceNameFormatter(name), arrayType, gType)
+}
+
+// sliceOf returns the SliceType of the merged elements, []interface{} if the array was empty
+func (p *Parser) sliceOf(arrayType GeneratedType) GeneratedType {
+	if arrayType == nil {
+		return &SliceType{InterfaceType}
+	}
+
+	return &SliceType{p.yoitsu.universe.FindType(arrayType)}
 }
 
 func (p *Parser) ParseObject(name string, obj JsonMap) (GeneratedType, error) {
@@ -104,38 +155,61 @@ func (p *Parser) ParseObject(name string, obj JsonMap) (GeneratedType, error) {
 		return nil, ErrNoData
 	}
 
-	st := StructType{
-		Name:   toSafeGoName(name),
-		Fields: make(map[string]*StructField),
-	}
-
+	st := p.newStructType(name)
 	for jsonName, jsonObject := range obj {
 		gType, err := p.Parse(name+jsonName, jsonObject)
 		if err != nil {
 			return nil, err
 		}
 
-		if stGType, ok := gType.(*StructType); ok {
-			stGType.tag = jsonName
-		}
+		st.addField(jsonName, gType, jsonObject == nil)
+	}
 
-		st.Fields[jsonName] = &StructField{
-			Type: gType,
-			Tag:  jsonName,
-		}
+	return p.yoitsu.universe.FindType(st), nil
+}
+
+// newStructType returns an empty StructType for a single sample of a JsonMap, see StructType.addField
+func (p *Parser) newStructType(name string) *StructType {
+	return &StructType{
+		Name:     toSafeGoName(name),
+		Fields:   make(map[string]*StructField),
+		Samples:  1,
+		optional: p.yoitsu.optionalFields,
+		unions:   p.yoitsu.unions,
+		tagKeys:  p.yoitsu.structTags,
+	}
+}
+
+// addField adds the field jsonName of the sample, null is true if its value is null
+func (st *StructType) addField(jsonName string, gType GeneratedType, null bool) {
+	if stGType, ok := gType.(*StructType); ok {
+		stGType.tag = jsonName
 	}
 
-	return p.yoitsu.universe.FindType(&st), nil
+	field := &StructField{
+		Type:    gType,
+		Tag:     jsonName,
+		Present: 1,
+	}
+	if null {
+		field.Null = 1
+	}
+	st.Fields[jsonName] = field
 }
 
 func (p *Parser) ParseNative(obj JsonObject) (GeneratedType, error) {
 	switch obj.(type) {
+	case json.Number:
+		return p.parseNumber(obj.(json.Number))
 	case float64:
 		return p.parseFloat64(obj.(float64))
 	case string:
 		return p.parseString(obj.(string))
 	case bool:
 		return BoolType, nil
+	case time.Time:
+		// Sources decoding formats with a native datetime, see TreeSource
+		return TimeType, nil
 	case nil:
 		return InterfaceType, nil
 	}
@@ -146,17 +220,43 @@ func (p *Parser) ParseNative(obj JsonObject) (GeneratedType, error) {
 func (p *Parser) parseString(s string) (GeneratedType, error) {
 	for _, parser := range p.stringParsers {
 		if gType, ok := parser(s); ok {
-			return gType, nil
+			return withParent(gType, StringType), nil
 		}
 	}
 
 	return StringType, nil
 }
 
+// parseNumber first tries the registered float64 NativeTypeParser's, and otherwise returns IntType or Int64Type
+// if the number is integral, Float64Type if not
+func (p *Parser) parseNumber(n json.Number) (GeneratedType, error) {
+	f, err := n.Float64()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, parser := range p.floatParsers {
+		if gType, ok := parser(f); ok {
+			return withParent(gType, Float64Type), nil
+		}
+	}
+
+	i, err := n.Int64()
+	if err != nil {
+		return Float64Type, nil
+	}
+
+	if i < math.MinInt32 || i > math.MaxInt32 {
+		return Int64Type, nil
+	}
+
+	return IntType, nil
+}
+
 func (p *Parser) parseFloat64(f float64) (GeneratedType, error) {
 	for _, parser := range p.floatParsers {
 		if gType, ok := parser(f); ok {
-			return gType, nil
+			return withParent(gType, Float64Type), nil
 		}
 	}
 
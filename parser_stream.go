@@ -0,0 +1,325 @@
+package yoitsu
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ParseStream is ParseRoot, reading the json token by token from r instead of decoding it into a JsonObject first.
+// Elements of arrays are merged as they're read, memory is proportional to the GeneratedType, not the data
+func (p *Parser) ParseStream(name string, r io.Reader) (GeneratedType, error) {
+	gType, err := newStreamParser(p, r, nil).parseRoot(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.cleanupRoot(gType)
+}
+
+type streamParser struct {
+	p       *Parser
+	decoder *json.Decoder
+	// unique collects the values of the elements of a root JsonArray for the ById accessors, may be nil
+	unique *uniqueValues
+	// primitives is reused for the fields of every element added to unique
+	primitives map[string]json.Token
+	// values is true if the input is a sequence of json values, the elements of the root JsonArray, see
+	// streamValuesSource
+	values bool
+}
+
+// streamValuesSource may be implemented by a StreamSource whose Reader returns a sequence of json values instead of a
+// single one, e.g. ndjson. The values are parsed as the elements of a root JsonArray
+type streamValuesSource interface {
+	StreamSource
+	streamValues() bool
+}
+
+func newStreamParser(p *Parser, r io.Reader, unique *uniqueValues) *streamParser {
+	// UseNumber for the same reason as getRootFromSrc
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	return &streamParser{
+		p:       p,
+		decoder: decoder,
+		unique:  unique,
+	}
+}
+
+// parseRoot returns nil if the root is null, like ParseRoots skips nil roots
+func (sp *streamParser) parseRoot(name string) (GeneratedType, error) {
+	if sp.values {
+		return sp.parseArray(name, true)
+	}
+
+	tok, err := sp.decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok == json.Delim('[') {
+		return sp.parseArray(name, true)
+	}
+
+	sp.unique.invalidate()
+	if tok == nil {
+		return nil, nil
+	}
+	return sp.parseValue(name, tok, nil)
+}
+
+// parseValue parses the value starting with tok. If primitives is non-nil and the value is a JsonMap, the tokens of
+// its fields are added to it
+func (sp *streamParser) parseValue(name string, tok json.Token, primitives map[string]json.Token) (GeneratedType, error) {
+	switch tok {
+	case json.Delim('['):
+		return sp.parseArray(name, false)
+	case json.Delim('{'):
+		return sp.parseObject(name, primitives)
+	}
+
+	return sp.p.ParseNative(tok)
+}
+
+// parseArray is Parser.ParseArray, the opening [ has been read. Root arrays are sampled, see Sampling
+func (sp *streamParser) parseArray(name string, root bool) (GeneratedType, error) {
+	if root && sp.p.yoitsu.sampling.enabled() {
+		return sp.parseSampledArray(name)
+	}
+
+	var arrayType GeneratedType
+	for sp.decoder.More() {
+		var err error
+		if arrayType, err = sp.parseElement(name, arrayType, root); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := sp.closeArray(root); err != nil {
+		return nil, err
+	}
+
+	return sp.p.sliceOf(arrayType), nil
+}
+
+// closeArray reads the closing ], there is none after a sequence of values
+func (sp *streamParser) closeArray(root bool) error {
+	if root && sp.values {
+		return nil
+	}
+
+	_, err := sp.decoder.Token()
+	return err
+}
+
+// parseSampledArray is Parser.parseSampledArray, elements which aren't picked are skipped without parsing them.
+// Once a limit is hit, the rest of the array isn't read
+func (sp *streamParser) parseSampledArray(name string) (GeneratedType, error) {
+	var (
+		s         = newSampler(sp.p.yoitsu.sampling)
+		res       = newReservoir[json.RawMessage](sp.p.yoitsu.sampling.Reservoir)
+		start     = sp.decoder.InputOffset()
+		arrayType GeneratedType
+		err       error
+	)
+
+	for sp.decoder.More() {
+		if !s.more(sp.decoder.InputOffset() - start) {
+			break
+		}
+
+		if s.reservoir() {
+			var raw json.RawMessage
+			if err = sp.decoder.Decode(&raw); err != nil {
+				return nil, err
+			}
+
+			res.offer(s.rng, raw)
+			continue
+		}
+
+		s.used()
+		if arrayType, err = sp.parseElement(name, arrayType, true); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, raw := range res.values() {
+		s.used()
+
+		element := newStreamParser(sp.p, bytes.NewReader(raw), sp.unique)
+		if arrayType, err = element.parseElement(name, arrayType, true); err != nil {
+			return nil, err
+		}
+	}
+
+	sp.p.yoitsu.sample.add(s.sample)
+
+	if s.sample.Truncated || s.sample.Elements < s.sample.Read {
+		// Values of the skipped elements are unknown, so no field is known to be unique
+		sp.unique.invalidate()
+	}
+
+	if !s.sample.Truncated {
+		if err = sp.closeArray(true); err != nil {
+			return nil, err
+		}
+	}
+
+	return sp.p.sliceOf(arrayType), nil
+}
+
+// parseElement reads the next element of an array, and merges it into the types of the previous elements. The
+// primitives of the elements of a root array are added to unique
+func (sp *streamParser) parseElement(name string, arrayType GeneratedType, root bool) (GeneratedType, error) {
+	tok, err := sp.decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	var primitives map[string]json.Token
+	if root && sp.unique != nil {
+		if tok != json.Delim('{') {
+			sp.unique.invalidate()
+		} else {
+			if sp.primitives == nil {
+				sp.primitives = make(map[string]json.Token)
+			}
+			primitives = sp.primitives
+			clear(primitives)
+		}
+	}
+
+	gType, err := sp.parseValue(SliceNameFormatter(name), tok, primitives)
+	if err != nil {
+		return nil, err
+	}
+
+	if primitives != nil {
+		sp.unique.add(primitives)
+	}
+
+	return sp.p.mergeSample(SliceNameFormatter(name), arrayType, gType)
+}
+
+// parseObject is Parser.ParseObject, the opening { has been read
+func (sp *streamParser) parseObject(name string, primitives map[string]json.Token) (GeneratedType, error) {
+	st := sp.p.newStructType(name)
+	for sp.decoder.More() {
+		key, err := sp.decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		jsonName, ok := key.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: unexpected object key %v", ErrUnknownType, key)
+		}
+
+		tok, err := sp.decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		gType, err := sp.parseValue(name+jsonName, tok, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		st.addField(jsonName, gType, tok == nil)
+
+		if primitives != nil {
+			primitives[jsonName] = tok
+		}
+	}
+
+	// Closing }
+	if _, err := sp.decoder.Token(); err != nil {
+		return nil, err
+	}
+
+	if len(st.Fields) == 0 {
+		return nil, ErrNoData
+	}
+
+	return sp.p.yoitsu.universe.FindType(st), nil
+}
+
+// streamJsonTypes parses every sample of the Source, streaming those implementing StreamSource. See WithStreaming
+func (y *Yoitsu) streamJsonTypes(ctx context.Context) (GeneratedType, error) {
+	samples := []Source{y.src}
+	if ss, ok := y.src.(SamplesSource); ok {
+		var err error
+		if samples, err = ss.Samples(); err != nil {
+			return nil, err
+		}
+	}
+
+	if y.accessors.Generate && y.accessors.ById {
+		y.uniqueValues = newUniqueValues()
+	}
+
+	var gType GeneratedType
+	for _, sample := range samples {
+		sampleType, err := y.streamSample(ctx, sample)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", sample.Name(), err)
+		}
+
+		if sampleType == nil {
+			continue
+		}
+
+		if gType, err = y.parser.mergeSample(y.src.Name(), gType, sampleType); err != nil {
+			return nil, err
+		}
+	}
+
+	return y.parser.cleanupRoot(gType)
+}
+
+// streamSample returns nil if the root of the sample is null
+func (y *Yoitsu) streamSample(ctx context.Context, sample Source) (GeneratedType, error) {
+	streamSrc, ok := sample.(StreamSource)
+	if !ok {
+		root, err := getRootFromSrc(ctx, sample)
+		if err != nil {
+			return nil, err
+		}
+
+		y.uniqueValues.addRoot(root)
+		if root == nil {
+			return nil, nil
+		}
+		return y.parser.parseRoot(y.src.Name(), root)
+	}
+
+	r, err := streamSrc.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	sp := newStreamParser(y.parser, &contextReader{ctx: ctx, r: r}, y.uniqueValues)
+	if valuesSrc, ok := sample.(streamValuesSource); ok {
+		sp.values = valuesSrc.streamValues()
+	}
+	return sp.parseRoot(y.src.Name())
+}
+
+// contextReader stops reading once the context is done
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (cr *contextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.r.Read(p)
+}
@@ -1,22 +1,146 @@
 package yoitsu
 
-import "time"
+import (
+	"math"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Fesaa/yoitsu/types"
+)
 
 type NativeTypeParser func(JsonObject) (GeneratedType, bool)
 
-// TimeParser is an example implementation of a NativeTypeParser for time.Time
+// NamedParser pairs a NativeTypeParser with the GeneratedType it must be registered on
+type NamedParser struct {
+	Parent GeneratedType
+	Parser NativeTypeParser
+}
+
+// NamedParsers holds the NativeTypeParser's which can be referenced by name in a Config.
+// Add your own to make them available to RunConfig
+var NamedParsers = map[string]NamedParser{
+	"time":     {Parent: StringType, Parser: TimeParser},
+	"date":     {Parent: StringType, Parser: DateParser},
+	"unix":     {Parent: Float64Type, Parser: UnixTimeParser},
+	"duration": {Parent: StringType, Parser: DurationParser},
+	"uuid":     {Parent: StringType, Parser: UUIDParser},
+	"url":      {Parent: StringType, Parser: URLParser},
+	"addr":     {Parent: StringType, Parser: AddrParser},
+}
+
+// DefaultNativeParsers are registered by WithDefaultNativeParsers, in order. UnixTimeParser is not included, as it
+// can't tell timestamps from ids or counts in the same range, register "unix" by name instead
+var DefaultNativeParsers = []NamedParser{
+	NamedParsers["time"],
+	NamedParsers["date"],
+	NamedParsers["duration"],
+	NamedParsers["uuid"],
+	NamedParsers["url"],
+	NamedParsers["addr"],
+}
+
+// TimeParser is an example implementation of a NativeTypeParser for time.Time. Fractional seconds are accepted,
+// like in time.RFC3339Nano
 //
 // Parser.RegisterNativeType(StringType, TimeParser)
 func TimeParser(obj JsonObject) (GeneratedType, bool) {
+	return layoutParser(obj, time.RFC3339, TimeType)
+}
+
+// DateParser returns DateType for strings in the time.DateOnly layout
+func DateParser(obj JsonObject) (GeneratedType, bool) {
+	return layoutParser(obj, time.DateOnly, DateType)
+}
+
+func layoutParser(obj JsonObject, layout string, gType GeneratedType) (GeneratedType, bool) {
 	s, ok := obj.(string)
 	if !ok {
 		return nil, false
 	}
 
-	_, err := time.Parse(time.RFC3339, s)
-	if err != nil {
-		return TimeType, true
+	if _, err := time.Parse(layout, s); err != nil {
+		return nil, false
+	}
+
+	return gType, true
+}
+
+var (
+	// unixTimeMin and unixTimeMax bound the seconds UnixTimeParser accepts, 2000-01-01 and 2100-01-01
+	unixTimeMin = float64(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).Unix())
+	unixTimeMax = float64(time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC).Unix())
+)
+
+// UnixTimeParser returns UnixTimeType for integral numbers between 2000 and 2100 when seen as seconds since the
+// unix epoch. Register on Float64Type
+func UnixTimeParser(obj JsonObject) (GeneratedType, bool) {
+	f, ok := obj.(float64)
+	if !ok {
+		return nil, false
+	}
+
+	if f != math.Trunc(f) || f < unixTimeMin || f >= unixTimeMax {
+		return nil, false
+	}
+
+	return UnixTimeType, true
+}
+
+// DurationParser returns DurationType for strings accepted by time.ParseDuration, which contain a unit
+func DurationParser(obj JsonObject) (GeneratedType, bool) {
+	s, ok := obj.(string)
+	if !ok || strings.TrimLeft(s, "+-0123456789.") == "" {
+		return nil, false
+	}
+
+	if _, err := time.ParseDuration(s); err != nil {
+		return nil, false
+	}
+
+	return DurationType, true
+}
+
+// UUIDParser returns UUIDType for strings in the canonical UUID form
+func UUIDParser(obj JsonObject) (GeneratedType, bool) {
+	s, ok := obj.(string)
+	if !ok {
+		return nil, false
+	}
+
+	if _, err := types.ParseUUID(s); err != nil {
+		return nil, false
+	}
+
+	return UUIDType, true
+}
+
+// URLParser returns URLType for absolute urls with a host
+func URLParser(obj JsonObject) (GeneratedType, bool) {
+	s, ok := obj.(string)
+	if !ok {
+		return nil, false
+	}
+
+	u, err := url.Parse(s)
+	if err != nil || !u.IsAbs() || u.Host == "" {
+		return nil, false
+	}
+
+	return URLType, true
+}
+
+// AddrParser returns AddrType for IPv4 and IPv6 addresses
+func AddrParser(obj JsonObject) (GeneratedType, bool) {
+	s, ok := obj.(string)
+	if !ok {
+		return nil, false
+	}
+
+	if _, err := netip.ParseAddr(s); err != nil {
+		return nil, false
 	}
 
-	return nil, false
+	return AddrType, true
 }
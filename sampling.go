@@ -0,0 +1,173 @@
+package yoitsu
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"time"
+)
+
+// Sampling limits the elements of a root JsonArray the types are inferred from, see WithSampling.
+// The limits can be combined, the reservoir is then filled from the elements read within the other limits
+type Sampling struct {
+	// First only reads the first First elements
+	First int
+	// Reservoir infers from a uniformly random sample of Reservoir elements, chosen with Seed. The same Seed always
+	// picks the same elements, with or without WithStreaming
+	Reservoir int
+	Seed      uint64
+	// Bytes stops reading elements once Bytes of the array have been read. Requires WithStreaming, the json is
+	// already in memory otherwise
+	Bytes int64
+	// Duration stops reading elements once Duration has passed. The output is not reproducible when this limit is hit
+	Duration time.Duration
+}
+
+func (s Sampling) enabled() bool {
+	return s.First > 0 || s.Reservoir > 0 || s.Bytes > 0 || s.Duration > 0
+}
+
+// Sample describes the elements of the root JsonArray's the types were inferred from, see Yoitsu.Sample
+type Sample struct {
+	// Elements is the amount of elements the types were inferred from
+	Elements int
+	// Read is the amount of elements read
+	Read int
+	// Truncated is true if not every element was read
+	Truncated bool
+}
+
+func (s Sample) String() string {
+	switch {
+	case !s.Truncated:
+		return fmt.Sprintf("a sample of %d of the %d elements of the root array", s.Elements, s.Read)
+	case s.Elements == s.Read:
+		return fmt.Sprintf("the first %d elements of the root array", s.Elements)
+	}
+	return fmt.Sprintf("a sample of %d of the first %d elements of the root array", s.Elements, s.Read)
+}
+
+func (s *Sample) add(other Sample) {
+	s.Elements += other.Elements
+	s.Read += other.Read
+	s.Truncated = s.Truncated || other.Truncated
+}
+
+// sampler decides which elements of a root JsonArray are used
+type sampler struct {
+	sampling Sampling
+	deadline time.Time
+	rng      *rand.Rand
+	sample   Sample
+}
+
+func newSampler(sampling Sampling) *sampler {
+	return &sampler{
+		sampling: sampling,
+		deadline: time.Now().Add(sampling.Duration),
+		rng:      rand.New(rand.NewPCG(sampling.Seed, sampling.Seed)),
+	}
+}
+
+// more is called before reading the next element with the amount of bytes of the array read so far, returns false
+// if the element should not be read
+func (s *sampler) more(read int64) bool {
+	switch {
+	case s.sampling.First > 0 && s.sample.Read >= s.sampling.First,
+		s.sampling.Bytes > 0 && read >= s.sampling.Bytes,
+		s.sampling.Duration > 0 && !time.Now().Before(s.deadline):
+		s.sample.Truncated = true
+		return false
+	}
+
+	s.sample.Read++
+	return true
+}
+
+// reservoir returns true if the read elements should be offered to a reservoir, instead of being used directly
+func (s *sampler) reservoir() bool {
+	return s.sampling.Reservoir > 0
+}
+
+// used is called for every element the types are inferred from
+func (s *sampler) used() {
+	s.sample.Elements++
+}
+
+// reservoir holds a uniformly random sample of the offered elements (Algorithm R), in the order they were offered
+type reservoir[T any] struct {
+	size    int
+	offered int
+	items   []reservoirItem[T]
+}
+
+type reservoirItem[T any] struct {
+	index int
+	value T
+}
+
+func newReservoir[T any](size int) *reservoir[T] {
+	return &reservoir[T]{size: size}
+}
+
+func (r *reservoir[T]) offer(rng *rand.Rand, value T) {
+	item := reservoirItem[T]{index: r.offered, value: value}
+	r.offered++
+
+	if len(r.items) < r.size {
+		r.items = append(r.items, item)
+		return
+	}
+
+	if i := rng.IntN(r.offered); i < r.size {
+		r.items[i] = item
+	}
+}
+
+func (r *reservoir[T]) values() []T {
+	slices.SortFunc(r.items, func(a, b reservoirItem[T]) int {
+		return a.index - b.index
+	})
+
+	values := make([]T, len(r.items))
+	for i, item := range r.items {
+		values[i] = item.value
+	}
+	return values
+}
+
+// parseSampledArray is ParseArray for a root JsonArray, only parsing the elements picked by Sampling
+func (p *Parser) parseSampledArray(name string, array JsonArray) (GeneratedType, error) {
+	var (
+		s         = newSampler(p.yoitsu.sampling)
+		res       = newReservoir[JsonObject](p.yoitsu.sampling.Reservoir)
+		arrayType GeneratedType
+		err       error
+	)
+
+	for _, v := range array {
+		if !s.more(0) {
+			break
+		}
+
+		if s.reservoir() {
+			res.offer(s.rng, v)
+			continue
+		}
+
+		s.used()
+		if arrayType, err = p.parseElement(name, arrayType, v); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, v := range res.values() {
+		s.used()
+		if arrayType, err = p.parseElement(name, arrayType, v); err != nil {
+			return nil, err
+		}
+	}
+
+	p.yoitsu.sample.add(s.sample)
+	return p.sliceOf(arrayType), nil
+}
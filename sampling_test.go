@@ -0,0 +1,98 @@
+package yoitsu
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func sampleReservoir(size int, n int, seed uint64) []int {
+	rng := rand.New(rand.NewPCG(seed, seed))
+	res := newReservoir[int](size)
+	for i := range n {
+		res.offer(rng, i)
+	}
+	return res.values()
+}
+
+func TestReservoir(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+		n    int
+		want int
+	}{
+		{"empty", 3, 0, 0},
+		{"fewer offered than size", 5, 3, 3},
+		{"as many offered as size", 3, 3, 3},
+		{"more offered than size", 3, 100, 3},
+		{"size zero", 0, 10, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := sampleReservoir(tt.size, tt.n, 1)
+
+			if len(values) != tt.want {
+				t.Fatalf("got %d values, want %d", len(values), tt.want)
+			}
+			if !slices.IsSorted(values) {
+				t.Errorf("values %v are not in the order they were offered", values)
+			}
+			if len(slices.Compact(slices.Clone(values))) != len(values) {
+				t.Errorf("values %v contain duplicates", values)
+			}
+			for _, v := range values {
+				if v < 0 || v >= tt.n {
+					t.Errorf("value %d was never offered", v)
+				}
+			}
+			if tt.n <= tt.size {
+				for i, v := range values {
+					if v != i {
+						t.Errorf("values %v, want every offered value", values)
+						break
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestReservoirSeeds(t *testing.T) {
+	samples := make(map[string]bool)
+	for seed := range uint64(20) {
+		values := sampleReservoir(5, 1000, seed)
+		if again := sampleReservoir(5, 1000, seed); !slices.Equal(values, again) {
+			t.Errorf("seed %d: got %v and %v", seed, values, again)
+		}
+		samples[fmt.Sprint(values)] = true
+	}
+
+	if len(samples) < 2 {
+		t.Errorf("every seed picked the same values")
+	}
+}
+
+func TestReservoirUniform(t *testing.T) {
+	const (
+		size  = 3
+		n     = 10
+		seeds = 10000
+	)
+
+	counts := make([]int, n)
+	for seed := range uint64(seeds) {
+		for _, v := range sampleReservoir(size, n, seed) {
+			counts[v]++
+		}
+	}
+
+	want := seeds * size / n
+	for v, count := range counts {
+		if count < want*9/10 || count > want*11/10 {
+			t.Errorf("value %d picked %d times, want about %d", v, count, want)
+		}
+	}
+}
@@ -0,0 +1,432 @@
+package yoitsu
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// jsonSchema is the subset of JSON Schema (draft 2020-12) used to construct a GeneratedType
+type jsonSchema struct {
+	Ref                  string                 `json:"$ref"`
+	Title                string                 `json:"title"`
+	Type                 schemaTypes            `json:"type"`
+	Format               string                 `json:"format"`
+	Enum                 []interface{}          `json:"enum"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	Required             []string               `json:"required"`
+	AdditionalProperties *jsonSchema            `json:"additionalProperties"`
+	Items                *jsonSchema            `json:"items"`
+	AnyOf                []*jsonSchema          `json:"anyOf"`
+	OneOf                []*jsonSchema          `json:"oneOf"`
+	AllOf                []*jsonSchema          `json:"allOf"`
+	// Nullable is the OpenAPI 3.0 way of adding null to the type
+	Nullable bool `json:"nullable"`
+
+	// boolean is set for the schemas true and false
+	boolean *bool
+}
+
+func (s *jsonSchema) UnmarshalJSON(data []byte) error {
+	var b bool
+	if err := json.Unmarshal(data, &b); err == nil {
+		s.boolean = &b
+		return nil
+	}
+
+	type plain jsonSchema
+	return json.Unmarshal(data, (*plain)(s))
+}
+
+// schemaTypes is the type keyword, which is either a string or an array of strings
+type schemaTypes []string
+
+func (t *schemaTypes) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = schemaTypes{single}
+		return nil
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+	*t = multiple
+	return nil
+}
+
+// schemaFormats maps the format keyword to the NativeType used, formats not listed are emitted as their type
+var schemaFormats = map[string]GeneratedType{
+	"date-time": TimeType,
+	"date":      DateType,
+	"uuid":      UUIDType,
+	"uri":       URLType,
+	"ipv4":      AddrType,
+	"ipv6":      AddrType,
+	"int32":     IntType,
+	"int64":     Int64Type,
+	"float":     Float64Type,
+	"double":    Float64Type,
+}
+
+// schemaBuilder constructs GeneratedType's from the schemas in doc. $ref's are resolved as json pointers into doc,
+// every $ref is only constructed once
+type schemaBuilder struct {
+	p   *Parser
+	doc interface{}
+
+	refs map[string]GeneratedType
+	// resolving holds the name reserved for the $ref's being resolved, see structRefType
+	resolving map[string]string
+	recursive map[string]bool
+}
+
+func newSchemaBuilder(p *Parser, doc interface{}) *schemaBuilder {
+	return &schemaBuilder{
+		p:         p,
+		doc:       doc,
+		refs:      make(map[string]GeneratedType),
+		resolving: make(map[string]string),
+		recursive: make(map[string]bool),
+	}
+}
+
+// build returns the type described by the schema, nullable is true if the schema allows null
+func (b *schemaBuilder) build(name string, s *jsonSchema) (gType GeneratedType, nullable bool, err error) {
+	if s == nil {
+		return InterfaceType, false, nil
+	}
+
+	if s.boolean != nil {
+		if !*s.boolean {
+			return nil, false, fmt.Errorf("%w: %s allows no values", ErrUnsupportedSchema, name)
+		}
+		return InterfaceType, false, nil
+	}
+
+	if s.Ref != "" {
+		gType, err = b.resolve(s.Ref)
+		return gType, false, err
+	}
+
+	if len(s.AllOf) > 0 {
+		return b.buildAllOf(name, s)
+	}
+
+	if variants := append(slices.Clone(s.AnyOf), s.OneOf...); len(variants) > 0 {
+		return b.buildVariants(name, variants)
+	}
+
+	types := s.Type
+	if len(types) == 0 {
+		types = enumTypes(s.Enum)
+	}
+	if len(types) == 0 && len(s.Properties) > 0 {
+		types = schemaTypes{"object"}
+	}
+	if len(types) == 0 && s.Items != nil {
+		types = schemaTypes{"array"}
+	}
+
+	for _, t := range types {
+		if t == "null" {
+			nullable = true
+			continue
+		}
+
+		tType, err := b.buildType(name, t, s)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if gType == nil {
+			gType = tType
+			continue
+		}
+
+		gType, err = mergeTypes(b.p.yoitsu.unions, name, gType, tType)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	nullable = nullable || s.Nullable
+	if gType == nil {
+		return InterfaceType, nullable, nil
+	}
+	return gType, nullable, nil
+}
+
+func (b *schemaBuilder) buildType(name string, t string, s *jsonSchema) (GeneratedType, error) {
+	if format, ok := schemaFormats[s.Format]; ok {
+		switch {
+		case t == "string" && !isNumeric(format):
+			return format, nil
+		case (t == "integer" || t == "number") && isNumeric(format):
+			return format, nil
+		}
+	}
+
+	switch t {
+	case "string":
+		return StringType, nil
+	case "integer":
+		return IntType, nil
+	case "number":
+		return Float64Type, nil
+	case "boolean":
+		return BoolType, nil
+	case "array":
+		itemType, _, err := b.build(SliceNameFormatter(name), s.Items)
+		if err != nil {
+			return nil, err
+		}
+		return &SliceType{itemType}, nil
+	case "object":
+		return b.buildObject(name, s)
+	}
+
+	return nil, fmt.Errorf("%w: %s has unknown type %q", ErrUnsupportedSchema, name, t)
+}
+
+// buildObject returns a StructType if the schema has properties, and a MapType otherwise
+func (b *schemaBuilder) buildObject(name string, s *jsonSchema) (GeneratedType, error) {
+	if len(s.Properties) == 0 {
+		closed := s.AdditionalProperties != nil && s.AdditionalProperties.boolean != nil && !*s.AdditionalProperties.boolean
+		if !closed {
+			valueType, _, err := b.build(name+"Value", s.AdditionalProperties)
+			if err != nil {
+				return nil, err
+			}
+			return &MapType{ValueType: valueType}, nil
+		}
+	}
+
+	st := b.p.newStructType(name)
+
+	for jsonName, property := range s.Properties {
+		fType, nullable, err := b.build(name+jsonName, property)
+		if err != nil {
+			return nil, err
+		}
+
+		field := &StructField{
+			Type: fType,
+			Tag:  jsonName,
+		}
+		if slices.Contains(s.Required, jsonName) {
+			field.Present = 1
+		}
+		if nullable {
+			field.Null = 1
+		}
+		st.Fields[jsonName] = field
+	}
+
+	return b.p.yoitsu.universe.FindType(st), nil
+}
+
+// buildAllOf merges the properties of all subschemas into one object
+func (b *schemaBuilder) buildAllOf(name string, s *jsonSchema) (GeneratedType, bool, error) {
+	merged := &jsonSchema{
+		Properties: make(map[string]*jsonSchema),
+		Required:   slices.Clone(s.Required),
+	}
+	for k, v := range s.Properties {
+		merged.Properties[k] = v
+	}
+
+	for _, sub := range s.AllOf {
+		sub, err := b.deref(sub)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if len(sub.AllOf) > 0 {
+			return nil, false, fmt.Errorf("%w: %s has nested allOf", ErrUnsupportedSchema, name)
+		}
+
+		for k, v := range sub.Properties {
+			merged.Properties[k] = v
+		}
+		merged.Required = append(merged.Required, sub.Required...)
+	}
+
+	gType, err := b.buildObject(name, merged)
+	return gType, false, err
+}
+
+// buildVariants merges all variants of anyOf and oneOf, as if they were samples. See Unions
+func (b *schemaBuilder) buildVariants(name string, variants []*jsonSchema) (gType GeneratedType, nullable bool, err error) {
+	var merged bool
+	for _, variant := range variants {
+		vType, vNullable, err := b.build(name, variant)
+		if err != nil {
+			return nil, false, err
+		}
+
+		nullable = nullable || vNullable
+		// {"type": "null"} only makes the value nullable
+		if vNullable && vType.SameType(InterfaceType, false) {
+			continue
+		}
+
+		if gType == nil {
+			gType = vType
+			continue
+		}
+		if gType.SameType(vType, false) {
+			continue
+		}
+
+		// Merge copies, the variants may be $ref's used elsewhere
+		if !merged {
+			gType, merged = gType.Copy(), true
+		}
+		gType, err = mergeTypes(b.p.yoitsu.unions, name, gType, vType.Copy())
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	if gType == nil {
+		return InterfaceType, nullable, nil
+	}
+	if st, ok := gType.(*StructType); ok && merged {
+		// Named after the value, not after one of the variants
+		st.Name = toSafeGoName(name)
+	}
+	return gType, nullable, nil
+}
+
+// resolve returns the type of the schema the $ref points to, named after its last segment. A $ref used within the
+// schema it points to refers to the StructType by name, see structRefType
+func (b *schemaBuilder) resolve(ref string) (GeneratedType, error) {
+	if gType, ok := b.refs[ref]; ok {
+		return gType, nil
+	}
+
+	if name, ok := b.resolving[ref]; ok {
+		b.recursive[ref] = true
+		return &structRefType{name: name}, nil
+	}
+
+	s, err := b.lookup(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	segments := strings.Split(strings.TrimPrefix(ref, "#"), "/")
+	name := segments[len(segments)-1]
+	if name == "" {
+		name = "Root"
+	}
+
+	// Reserve the name, so recursion ends
+	b.resolving[ref] = toSafeGoName(name)
+	defer delete(b.resolving, ref)
+
+	gType, _, err := b.build(name, s)
+	if err != nil {
+		return nil, err
+	}
+
+	if st, ok := gType.(*StructType); b.recursive[ref] && (!ok || st.Type() != b.resolving[ref]) {
+		return nil, fmt.Errorf("%w: recursive $ref %s must be an object with properties", ErrUnsupportedSchema, ref)
+	}
+
+	b.refs[ref] = gType
+	return gType, nil
+}
+
+// deref follows the $ref of the schema, if any
+func (b *schemaBuilder) deref(s *jsonSchema) (*jsonSchema, error) {
+	if s == nil || s.Ref == "" {
+		return s, nil
+	}
+	return b.lookup(s.Ref)
+}
+
+// lookup returns the schema the json pointer ref points to, see lookupInto
+func (b *schemaBuilder) lookup(ref string) (*jsonSchema, error) {
+	var s jsonSchema
+	if err := b.lookupInto(ref, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// lookupInto decodes the value the json pointer ref points to into v, only references within the document
+// are supported
+func (b *schemaBuilder) lookupInto(ref string, v interface{}) error {
+	if !strings.HasPrefix(ref, "#") {
+		return fmt.Errorf("%w: only local $ref's are supported, got %s", ErrUnsupportedSchema, ref)
+	}
+
+	pointer, err := url.PathUnescape(strings.TrimPrefix(ref, "#"))
+	if err != nil {
+		return err
+	}
+
+	node := b.doc
+	for _, segment := range strings.Split(pointer, "/")[1:] {
+		segment = strings.NewReplacer("~1", "/", "~0", "~").Replace(segment)
+
+		switch n := node.(type) {
+		case map[string]interface{}:
+			node = n[segment]
+		case []interface{}:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(n) {
+				return fmt.Errorf("%w: $ref %s not found", ErrUnsupportedSchema, ref)
+			}
+			node = n[i]
+		default:
+			node = nil
+		}
+
+		if node == nil {
+			return fmt.Errorf("%w: $ref %s not found", ErrUnsupportedSchema, ref)
+		}
+	}
+
+	data, err := json.Marshal(node)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// enumTypes returns the json types of the values of an enum without type
+func enumTypes(values []interface{}) schemaTypes {
+	var types schemaTypes
+	add := func(t string) {
+		if !slices.Contains(types, t) {
+			types = append(types, t)
+		}
+	}
+
+	for _, v := range values {
+		switch v := v.(type) {
+		case string:
+			add("string")
+		case bool:
+			add("boolean")
+		case nil:
+			add("null")
+		case float64:
+			if v == float64(int64(v)) {
+				add("integer")
+			} else {
+				add("number")
+			}
+		}
+	}
+
+	return types
+}
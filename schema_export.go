@@ -0,0 +1,170 @@
+package yoitsu
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// nativeSchemas describes the NativeType's in JSON Schema, types not listed allow any value
+var nativeSchemas = map[string]map[string]interface{}{
+	StringType.Type():   {"type": "string"},
+	IntType.Type():      {"type": "integer"},
+	Int64Type.Type():    {"type": "integer"},
+	Float64Type.Type():  {"type": "number"},
+	BoolType.Type():     {"type": "boolean"},
+	TimeType.Type():     {"type": "string", "format": "date-time"},
+	DateType.Type():     {"type": "string", "format": "date"},
+	UnixTimeType.Type(): {"type": "integer"},
+	DurationType.Type(): {"type": "string"},
+	UUIDType.Type():     {"type": "string", "format": "uuid"},
+	URLType.Type():      {"type": "string", "format": "uri"},
+	AddrType.Type(): {
+		"type":  "string",
+		"anyOf": []interface{}{map[string]interface{}{"format": "ipv4"}, map[string]interface{}{"format": "ipv6"}},
+	},
+}
+
+// JsonSchema returns a JSON Schema (draft 2020-12) document describing the root type, StructType's are added to
+// $defs. Call Yoitsu.GenerateFile first
+func (y *Yoitsu) JsonSchema() ([]byte, error) {
+	if y.rootType == nil {
+		return nil, fmt.Errorf("no type generated. Call Yoitsu.GenerateFile first")
+	}
+
+	e := &schemaExporter{defs: make(map[string]interface{})}
+
+	doc := map[string]interface{}{
+		"$schema": jsonSchemaDialect,
+	}
+
+	if set, ok := y.rootType.(*typeSet); ok {
+		// StructType's add themselves, aliases are added under their name
+		for _, name := range set.names {
+			gType := set.types[name]
+			if s := e.schema(gType); gType.Type() != name {
+				e.defs[name] = s
+			}
+		}
+	} else {
+		for k, v := range e.schema(y.rootType) {
+			doc[k] = v
+		}
+		doc["title"] = y.src.Name()
+	}
+
+	if sample, ok := y.Sample(); ok {
+		doc["description"] = "Inferred from " + sample.String()
+	}
+
+	if len(e.defs) > 0 {
+		doc["$defs"] = e.defs
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// WriteJsonSchema writes Yoitsu.JsonSchema to Source.Name + ".schema.json" in dir
+func (y *Yoitsu) WriteJsonSchema(dir string) error {
+	b, err := y.JsonSchema()
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, y.src.Name()+".schema.json"), append(b, '\n'), 0o644)
+}
+
+type schemaExporter struct {
+	defs map[string]interface{}
+}
+
+func (e *schemaExporter) schema(gType GeneratedType) map[string]interface{} {
+	switch t := gType.(type) {
+	case *StructType:
+		return e.structSchema(t)
+	case *SliceType:
+		s := map[string]interface{}{"type": "array"}
+		if items := e.schema(t.SliceType); len(items) > 0 {
+			s["items"] = items
+		}
+		return s
+	case *MapType:
+		s := map[string]interface{}{"type": "object"}
+		if values := e.schema(t.ValueType); len(values) > 0 {
+			s["additionalProperties"] = values
+		}
+		return s
+	case *UnionType:
+		var variants []interface{}
+		for _, kind := range t.sortedKinds() {
+			variants = append(variants, e.schema(t.Variants[kind]))
+		}
+		return map[string]interface{}{"anyOf": variants}
+	case *structRefType:
+		return map[string]interface{}{"$ref": "#/$defs/" + t.Type()}
+	case *NativeType:
+		s := make(map[string]interface{})
+		for k, v := range nativeSchemas[t.Type()] {
+			s[k] = v
+		}
+		return s
+	}
+
+	return map[string]interface{}{}
+}
+
+// structSchema adds the StructType to $defs, and returns a $ref to it
+func (e *schemaExporter) structSchema(st *StructType) map[string]interface{} {
+	ref := map[string]interface{}{"$ref": "#/$defs/" + st.Type()}
+	if _, ok := e.defs[st.Type()]; ok {
+		return ref
+	}
+
+	properties := make(map[string]interface{})
+	def := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	// Reserve the name, so recursion ends
+	e.defs[st.Type()] = def
+
+	var required []string
+	for tag, field := range st.Fields {
+		property := e.schema(field.Type)
+		if field.Null > 0 {
+			property = nullable(property)
+		}
+		properties[tag] = property
+
+		if field.Present >= st.Samples {
+			required = append(required, tag)
+		}
+	}
+
+	if len(required) > 0 {
+		slices.Sort(required)
+		def["required"] = required
+	}
+
+	return ref
+}
+
+// nullable adds null to the allowed types of the schema
+func nullable(s map[string]interface{}) map[string]interface{} {
+	if len(s) == 0 {
+		return s
+	}
+
+	if t, ok := s["type"].(string); ok {
+		s["type"] = []string{t, "null"}
+		return s
+	}
+
+	return map[string]interface{}{
+		"anyOf": []interface{}{s, map[string]interface{}{"type": "null"}},
+	}
+}
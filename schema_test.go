@@ -0,0 +1,224 @@
+package yoitsu
+
+import (
+	"errors"
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// generateDecls generates the file, and returns its declarations with whitespace collapsed. Types are keyed by their
+// name, methods by Type.Method
+func generateDecls(t *testing.T, y *Yoitsu) map[string]string {
+	t.Helper()
+
+	if err := y.GenerateFile(); err != nil {
+		t.Fatalf("GenerateFile: %v", err)
+	}
+
+	decls := make(map[string]string)
+	for _, decl := range y.File.Decls {
+		var name string
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			if d.Tok != token.TYPE {
+				continue
+			}
+			d.Doc = nil
+			name = d.Specs[0].(*ast.TypeSpec).Name.Name
+		case *ast.FuncDecl:
+			d.Doc = nil
+			name = d.Name.Name
+			if d.Recv != nil {
+				name = strings.TrimPrefix(printNode(d.Recv.List[0].Type), "*") + "." + name
+			}
+		}
+		decls[name] = strings.Join(strings.Fields(printNode(decl)), " ")
+	}
+	return decls
+}
+
+// writeTemp writes content to a file in a temporary directory, and returns its path
+func writeTemp(t *testing.T, name string, content string) string {
+	t.Helper()
+
+	f := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(f, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func checkDecls(t *testing.T, got map[string]string, want map[string]string) {
+	t.Helper()
+
+	for name, decl := range want {
+		if got[name] != decl {
+			t.Errorf("%s:\ngot  %s\nwant %s", name, got[name], decl)
+		}
+	}
+}
+
+func TestSchema(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		want   map[string]string
+	}{
+		{
+			name: "oneOf of two $ref's",
+			schema: `{
+				"$defs": {
+					"Cat": {"type": "object", "required": ["meow"], "properties": {"meow": {"type": "string"}}},
+					"Dog": {"type": "object", "required": ["bark"], "properties": {"bark": {"type": "integer"}}}
+				},
+				"type": "object",
+				"required": ["pet", "cat"],
+				"properties": {
+					"pet": {"oneOf": [{"$ref": "#/$defs/Cat"}, {"$ref": "#/$defs/Dog"}]},
+					"cat": {"$ref": "#/$defs/Cat"}
+				}
+			}`,
+			want: map[string]string{
+				"Root":    "type Root struct { Cat Cat `json:\"cat\"` Pet Rootpet `json:\"pet\"` }",
+				"Cat":     "type Cat struct { Meow string `json:\"meow\"` }",
+				"Rootpet": "type Rootpet struct { Bark int `json:\"bark\"` Meow string `json:\"meow\"` }",
+			},
+		},
+		{
+			name: "oneOf of the same $ref",
+			schema: `{
+				"$defs": {"Cat": {"type": "object", "required": ["meow"], "properties": {"meow": {"type": "string"}}}},
+				"type": "object",
+				"required": ["pet"],
+				"properties": {"pet": {"anyOf": [{"$ref": "#/$defs/Cat"}, {"$ref": "#/$defs/Cat"}, {"type": "null"}]}}
+			}`,
+			want: map[string]string{
+				"Root": "type Root struct { Pet Cat `json:\"pet\"` }",
+				"Cat":  "type Cat struct { Meow string `json:\"meow\"` }",
+			},
+		},
+		{
+			name: "allOf",
+			schema: `{
+				"$defs": {"Named": {"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}}},
+				"allOf": [{"$ref": "#/$defs/Named"}, {"properties": {"age": {"type": "integer"}}}]
+			}`,
+			want: map[string]string{
+				"Root": "type Root struct { Age int `json:\"age\"` Name string `json:\"name\"` }",
+			},
+		},
+		{
+			name: "recursive $ref",
+			schema: `{
+				"$defs": {
+					"Node": {
+						"type": "object",
+						"required": ["children"],
+						"properties": {
+							"children": {"type": "array", "items": {"$ref": "#/$defs/Node"}},
+							"parent": {"$ref": "#/$defs/Node"}
+						}
+					}
+				},
+				"$ref": "#/$defs/Node"
+			}`,
+			want: map[string]string{
+				"Node": "type Node struct { Children []Node `json:\"children\"` Parent *Node `json:\"parent\"` }",
+			},
+		},
+		{
+			name: "formats and maps",
+			schema: `{
+				"type": "object",
+				"required": ["at", "id", "counts"],
+				"properties": {
+					"at": {"type": "string", "format": "date-time"},
+					"id": {"type": "string", "format": "uuid"},
+					"counts": {"type": "object", "additionalProperties": {"type": "integer", "format": "int64"}}
+				}
+			}`,
+			want: map[string]string{
+				"Root": "type Root struct { At time.Time `json:\"at\"` Counts map[string]int64 `json:\"counts\"` Id types.UUID `json:\"id\"` }",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := writeTemp(t, "root.schema.json", tt.schema)
+			checkDecls(t, generateDecls(t, New(NewJsonSchemaSource("Root", f))), tt.want)
+		})
+	}
+}
+
+func TestSchemaOptionalFields(t *testing.T) {
+	f := writeTemp(t, "root.schema.json", `{
+		"type": "object",
+		"required": ["a", "e", "n"],
+		"properties": {
+			"a": {"type": "string"},
+			"o": {"type": "integer"},
+			"n": {"type": ["string", "null"]},
+			"e": {"enum": ["x", "y"]},
+			"m": {"type": "object", "additionalProperties": false, "properties": {"k": {"type": "boolean"}}},
+			"l": {"type": "array", "items": {"type": "number"}},
+			"any": {}
+		}
+	}`)
+
+	tests := []struct {
+		optional OptionalFields
+		want     map[string]string
+	}{
+		{
+			optional: OptionalFieldsPointer,
+			want: map[string]string{
+				"Root": "type Root struct { A string `json:\"a\"` Any interface{} `json:\"any,omitempty\"` E string `json:\"e\"` " +
+					"L []float64 `json:\"l,omitempty\"` M *Rootm `json:\"m,omitempty\"` N *string `json:\"n,omitempty\"` " +
+					"O *int `json:\"o,omitempty\"` }",
+				"Rootm": "type Rootm struct { K *bool `json:\"k,omitempty\"` }",
+			},
+		},
+		{
+			optional: OptionalFieldsPlain,
+			want: map[string]string{
+				"Root": "type Root struct { A string `json:\"a\"` Any interface{} `json:\"any\"` E string `json:\"e\"` " +
+					"L []float64 `json:\"l\"` M Rootm `json:\"m\"` N string `json:\"n\"` O int `json:\"o\"` }",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		name, _ := tt.optional.MarshalText()
+		t.Run(string(name), func(t *testing.T) {
+			checkDecls(t, generateDecls(t, New(NewJsonSchemaSource("Root", f), WithOptionalFields(tt.optional))), tt.want)
+		})
+	}
+}
+
+func TestSchemaUnsupported(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+	}{
+		{"false", `false`},
+		{"external $ref", `{"$ref": "other.json#/a"}`},
+		{"missing $ref", `{"$ref": "#/$defs/missing"}`},
+		{"nested allOf", `{"$defs": {"A": {"allOf": [{"type": "object"}]}}, "allOf": [{"$ref": "#/$defs/A"}]}`},
+		{"unknown type", `{"type": "file"}`},
+		{"recursive array", `{"$defs": {"Tree": {"type": "array", "items": {"$ref": "#/$defs/Tree"}}}, "$ref": "#/$defs/Tree"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := writeTemp(t, "root.schema.json", tt.schema)
+			if err := New(NewJsonSchemaSource("Root", f)).GenerateFile(); !errors.Is(err, ErrUnsupportedSchema) {
+				t.Errorf("GenerateFile: got %v, want ErrUnsupportedSchema", err)
+			}
+		})
+	}
+}
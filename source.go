@@ -1,12 +1,12 @@
 package yoitsu
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"go/ast"
 	"go/token"
 	"io"
-	"net/http"
-	"net/url"
 	"os"
 	"strconv"
 )
@@ -18,34 +18,75 @@ type Source interface {
 	Name() string
 }
 
+// ContextSource may be implemented by a Source to support cancellation, see Yoitsu.GenerateFileContext
+type ContextSource interface {
+	Source
+	JsonContext(ctx context.Context) ([]byte, error)
+}
+
+// TreeSource may be implemented by a Source whose data isn't json. Root returns the data as the tree the Parser
+// walks: JsonMap, JsonArray, string, bool, nil, json.Number and time.Time
+type TreeSource interface {
+	Source
+	Root() (JsonObject, error)
+}
+
+// StreamSource may be implemented by a Source whose json can be read incrementally, see WithStreaming
+type StreamSource interface {
+	Source
+	Reader() (io.ReadCloser, error)
+}
+
+// TypeSource may be implemented by a Source which describes its type directly, e.g. a schema. Yoitsu uses the
+// returned type instead of parsing the samples, which are then only used by the accessors
+type TypeSource interface {
+	Source
+	GeneratedType(p *Parser) (GeneratedType, error)
+}
+
+// StructTagsSource may be implemented by a Source to emit additional struct tags next to json, e.g. yaml
+type StructTagsSource interface {
+	StructTags() []string
+}
+
 // LoadAbleSource returns the body, and required imports to load the data when using the generated file.
-// Implement this when you wish to generate Accessors. The provided sources both implement this interface
+// Implement this when you wish to generate Accessors. Most provided sources implement this interface
 type LoadAbleSource interface {
 	LoadMethod() (stmt *ast.BlockStmt, importSpec []ast.Spec)
 }
 
-func NewFileSource(name string, f string) Source {
-	return &fileSource{
-		f:    f,
-		name: name,
-	}
+// LoadDeclsSource may be implemented by a LoadAbleSource if LoadMethod requires package level declarations
+type LoadDeclsSource interface {
+	LoadDecls() []ast.Decl
 }
 
-func NewUrlSource(name string, u string, opts ...Option[urlSource]) Source {
-	us := urlSource{
-		url:  u,
-		name: name,
-	}
+// DeclsSource may be implemented by a Source to add declarations to the generated file, next to the types.
+// Decls is called after the types are generated
+type DeclsSource interface {
+	Decls() (decls []ast.Decl, importSpecs []ast.Spec)
+}
 
-	for _, opt := range opts {
-		opt(us)
-	}
+// SamplesSource is a Source made up of multiple samples of the same root type. Yoitsu parses every sample, and
+// merges the results into one type
+type SamplesSource interface {
+	Source
+	Samples() ([]Source, error)
+}
 
-	if us.httpClient == nil {
-		us.httpClient = http.DefaultClient
+// NewMultiSource returns a SamplesSource over the passed sources. Json returns the samples combined into one json
+// array if they're all arrays, and errors otherwise. A single sample is returned as is
+func NewMultiSource(name string, sources ...Source) SamplesSource {
+	return &multiSource{
+		name:    name,
+		sources: sources,
 	}
+}
 
-	return &us
+func NewFileSource(name string, f string) Source {
+	return &fileSource{
+		f:    f,
+		name: name,
+	}
 }
 
 type fileSource struct {
@@ -75,6 +116,10 @@ func (src *fileSource) Json() ([]byte, error) {
 	return src.b, nil
 }
 
+func (src *fileSource) Reader() (io.ReadCloser, error) {
+	return os.Open(src.f)
+}
+
 func (src *fileSource) Name() string {
 	return src.name
 }
@@ -148,113 +193,39 @@ func (src *fileSource) LoadMethod() (*ast.BlockStmt, []ast.Spec) {
 		}
 }
 
-type urlSource struct {
-	httpClient *http.Client
-	url        string
-	b          []byte
-	name       string
+type multiSource struct {
+	name    string
+	sources []Source
 }
 
-func (src *urlSource) Json() ([]byte, error) {
-	if src.b != nil {
-		return src.b, nil
+func (src *multiSource) Json() ([]byte, error) {
+	switch len(src.sources) {
+	case 0:
+		return nil, ErrNoData
+	case 1:
+		return src.sources[0].Json()
 	}
 
-	parsedUrl, err := url.Parse(src.url)
-	if err != nil {
-		return nil, err
+	roots := make([]interface{}, len(src.sources))
+	for i, sample := range src.sources {
+		root, err := getRootFromSrc(context.Background(), sample)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", sample.Name(), err)
+		}
+		roots[i] = root
 	}
 
-	src.url = parsedUrl.String()
-
-	resp, err := src.httpClient.Get(src.url)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	b, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+	combined := combineRoots(roots)
+	if combined == nil {
+		return nil, fmt.Errorf("%w: samples of %s are not all json arrays", ErrSamplesNotCombinable, src.name)
 	}
-	src.b = b
-	return b, nil
+	return json.Marshal(combined)
 }
 
-func (src *urlSource) Name() string {
+func (src *multiSource) Name() string {
 	return src.name
 }
 
-func (src *urlSource) LoadMethod() (*ast.BlockStmt, []ast.Spec) {
-	return &ast.BlockStmt{
-			List: []ast.Stmt{
-				&ast.AssignStmt{
-					Lhs: []ast.Expr{
-						ast.NewIdent("res"),
-						ast.NewIdent("err"),
-					},
-					Tok: token.DEFINE,
-					Rhs: []ast.Expr{
-						&ast.CallExpr{
-							Fun: &ast.SelectorExpr{
-								X:   ast.NewIdent("http"),
-								Sel: ast.NewIdent("Get"),
-							},
-							Args: []ast.Expr{
-								&ast.BasicLit{
-									Kind:  token.STRING,
-									Value: fmt.Sprintf(`"%s"`, src.url),
-								},
-							},
-						},
-					},
-				},
-				ifErrNotNilStmt(),
-				deferStmt("res.Body", "Close"),
-				&ast.AssignStmt{
-					Lhs: []ast.Expr{
-						ast.NewIdent("data"),
-						ast.NewIdent("err"),
-					},
-					Tok: token.DEFINE,
-					Rhs: []ast.Expr{
-						&ast.CallExpr{
-							Fun: &ast.SelectorExpr{
-								X:   ast.NewIdent("io"),
-								Sel: ast.NewIdent("ReadAll"),
-							},
-							Args: []ast.Expr{
-								ast.NewIdent("res.Body"),
-							},
-						},
-					},
-				},
-				ifErrNotNilStmt(),
-				unmarshallStmt(tokenReceiver, tokenData),
-			},
-		}, []ast.Spec{
-			&ast.ImportSpec{
-				Path: &ast.BasicLit{
-					Kind:  token.STRING,
-					Value: strconv.Quote("net/http"),
-				},
-			},
-			&ast.ImportSpec{
-				Path: &ast.BasicLit{
-					Kind:  token.STRING,
-					Value: strconv.Quote("io"),
-				},
-			},
-			&ast.ImportSpec{
-				Path: &ast.BasicLit{
-					Kind:  token.STRING,
-					Value: strconv.Quote("encoding/json"),
-				},
-			},
-		}
-}
-
-func UrlSourceWithHttpClient(c *http.Client) Option[urlSource] {
-	return func(source urlSource) {
-		source.httpClient = c
-	}
+func (src *multiSource) Samples() ([]Source, error) {
+	return src.sources, nil
 }
@@ -0,0 +1,85 @@
+package yoitsu
+
+import (
+	"go/ast"
+	"go/token"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// NewBytesSource uses the passed json directly. The generated LoadData unmarshals an embedded copy of it
+func NewBytesSource(name string, b []byte) Source {
+	return &bytesSource{
+		name: name,
+		b:    b,
+	}
+}
+
+// NewReaderSource reads all json from the reader on first use, e.g. os.Stdin.
+// The generated LoadData unmarshals an embedded copy of it
+func NewReaderSource(name string, r io.Reader) Source {
+	return &readerSource{
+		r: r,
+		bytesSource: bytesSource{
+			name: name,
+		},
+	}
+}
+
+type bytesSource struct {
+	name string
+	b    []byte
+}
+
+func (src *bytesSource) Json() ([]byte, error) {
+	return src.b, nil
+}
+
+func (src *bytesSource) Name() string {
+	return src.name
+}
+
+func (src *bytesSource) LoadMethod() (*ast.BlockStmt, []ast.Spec) {
+	return &ast.BlockStmt{
+		List: []ast.Stmt{
+			defineStmt([]string{"data"}, &ast.CallExpr{
+				Fun:  ast.NewIdent("[]byte"),
+				Args: []ast.Expr{embeddedLit(src.b)},
+			}),
+			unmarshallStmt(tokenReceiver, tokenData),
+		},
+	}, importSpecs("encoding/json")
+}
+
+// embeddedLit returns a raw string literal if possible, as these are easier to read in the generated file
+func embeddedLit(b []byte) *ast.BasicLit {
+	s := string(b)
+	if strings.Contains(s, "`") || strings.Contains(s, "\r") || !strconv.CanBackquote(strings.ReplaceAll(s, "\n", "")) {
+		return stringLit(s)
+	}
+
+	return &ast.BasicLit{
+		Kind:  token.STRING,
+		Value: "`" + s + "`",
+	}
+}
+
+type readerSource struct {
+	bytesSource
+	r io.Reader
+}
+
+func (src *readerSource) Json() ([]byte, error) {
+	if src.b != nil {
+		return src.b, nil
+	}
+
+	b, err := io.ReadAll(src.r)
+	if err != nil {
+		return nil, err
+	}
+
+	src.b = b
+	return b, nil
+}
@@ -0,0 +1,302 @@
+package yoitsu
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"slices"
+	"strconv"
+)
+
+// NewCsvSource reads csv from f, the header row is used as field tags, and every other row is an element of the
+// root JsonArray.
+//
+// A column is a bool if all its values are true or false, a number if they're all json numbers, otherwise a
+// string, which the registered NativeTypeParser's may specialise further. Empty values are seen as null.
+// The generated LoadData uses the same rules to parse the csv
+func NewCsvSource(name string, f string, opts ...Option[*csvSource]) Source {
+	cs := &csvSource{
+		f:     f,
+		name:  name,
+		comma: ',',
+	}
+
+	for _, opt := range opts {
+		opt(cs)
+	}
+
+	return cs
+}
+
+// CsvSourceWithComma sets the field delimiter, see csv.Reader
+func CsvSourceWithComma(comma rune) Option[*csvSource] {
+	return func(src *csvSource) {
+		src.comma = comma
+	}
+}
+
+type csvSource struct {
+	f     string
+	name  string
+	comma rune
+	root  JsonArray
+	// rawColumns holds the headers of the bool and number columns, these are valid json as is
+	rawColumns []string
+}
+
+func (src *csvSource) Root() (JsonObject, error) {
+	if src.root != nil {
+		return src.root, nil
+	}
+
+	file, err := os.Open(src.f)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comma = src.comma
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) < 2 {
+		return nil, fmt.Errorf("%w: csv has no rows", ErrNoData)
+	}
+
+	header, rows := records[0], records[1:]
+	for i, tag := range header {
+		if slices.Contains(header[:i], tag) {
+			return nil, fmt.Errorf("%s: duplicate header %q", src.f, tag)
+		}
+	}
+	src.rawColumns = nil
+
+	root := make(JsonArray, len(rows))
+	for i := range rows {
+		root[i] = make(JsonMap, len(header))
+	}
+
+	for col, tag := range header {
+		values := make([]string, 0, len(rows))
+		for _, row := range rows {
+			if col < len(row) {
+				values = append(values, row[col])
+			}
+		}
+
+		raw := rawKind(values)
+		if raw != "" {
+			src.rawColumns = append(src.rawColumns, tag)
+		}
+
+		for i, row := range rows {
+			var value JsonObject
+			switch {
+			case col >= len(row) || row[col] == "":
+				value = nil
+			case raw == "bool":
+				value = row[col] == "true"
+			case raw == "number":
+				value = json.Number(row[col])
+			default:
+				value = row[col]
+			}
+			root[i].(JsonMap)[tag] = value
+		}
+	}
+
+	src.root = root
+	return root, nil
+}
+
+// rawKind returns "bool" or "number" if all non-empty values are, otherwise an empty string
+func rawKind(values []string) string {
+	isBool, isNumber, empty := true, true, true
+
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		empty = false
+
+		isBool = isBool && (value == "true" || value == "false")
+		if isNumber {
+			_, err := strconv.ParseFloat(value, 64)
+			isNumber = err == nil && json.Valid([]byte(value))
+		}
+	}
+
+	switch {
+	case empty:
+		return ""
+	case isBool:
+		return "bool"
+	case isNumber:
+		return "number"
+	}
+	return ""
+}
+
+// Json returns the rows as a json array
+func (src *csvSource) Json() ([]byte, error) {
+	root, err := src.Root()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(root)
+}
+
+func (src *csvSource) Name() string {
+	return src.name
+}
+
+// LoadMethod converts every row into a json object, and unmarshalls those
+func (src *csvSource) LoadMethod() (*ast.BlockStmt, []ast.Spec) {
+	rawColumns := slices.Clone(src.rawColumns)
+	slices.Sort(rawColumns)
+
+	rawElts := make([]ast.Expr, len(rawColumns))
+	for i, tag := range rawColumns {
+		rawElts[i] = &ast.KeyValueExpr{
+			Key:   stringLit(tag),
+			Value: ast.NewIdent("true"),
+		}
+	}
+
+	header := &ast.IndexExpr{X: ast.NewIdent("records"), Index: &ast.BasicLit{Kind: token.INT, Value: "0"}}
+	rowValue := &ast.IndexExpr{X: ast.NewIdent("row"), Index: &ast.IndexExpr{X: header, Index: ast.NewIdent("i")}}
+
+	return &ast.BlockStmt{
+		List: []ast.Stmt{
+			defineStmt([]string{"f", "err"}, &ast.CallExpr{
+				Fun:  selectorExpr("os", "Open"),
+				Args: []ast.Expr{stringLit(src.f)},
+			}),
+			ifErrNotNilStmt(),
+			deferStmt("f", "Close"),
+			defineStmt([]string{"reader"}, &ast.CallExpr{
+				Fun:  selectorExpr("csv", "NewReader"),
+				Args: []ast.Expr{ast.NewIdent("f")},
+			}),
+			&ast.AssignStmt{
+				Lhs: []ast.Expr{selectorExpr("reader", "Comma")},
+				Tok: token.ASSIGN,
+				Rhs: []ast.Expr{&ast.BasicLit{Kind: token.CHAR, Value: strconv.QuoteRune(src.comma)}},
+			},
+			defineStmt([]string{"records", "err"}, &ast.CallExpr{Fun: selectorExpr("reader", "ReadAll")}),
+			ifErrNotNilStmt(),
+			&ast.IfStmt{
+				Cond: &ast.BinaryExpr{
+					X:  &ast.CallExpr{Fun: ast.NewIdent("len"), Args: []ast.Expr{ast.NewIdent("records")}},
+					Op: token.EQL,
+					Y:  &ast.BasicLit{Kind: token.INT, Value: "0"},
+				},
+				Body: &ast.BlockStmt{
+					List: []ast.Stmt{&ast.ReturnStmt{Results: []ast.Expr{ast.NewIdent("nil")}}},
+				},
+			},
+			defineStmt([]string{"raw"}, &ast.CompositeLit{
+				Type: ast.NewIdent("map[string]bool"),
+				Elts: rawElts,
+			}),
+			defineStmt([]string{"rows"}, &ast.CallExpr{
+				Fun: ast.NewIdent("make"),
+				Args: []ast.Expr{
+					ast.NewIdent("[]map[string]json.RawMessage"),
+					&ast.BasicLit{Kind: token.INT, Value: "0"},
+					&ast.BinaryExpr{
+						X:  &ast.CallExpr{Fun: ast.NewIdent("len"), Args: []ast.Expr{ast.NewIdent("records")}},
+						Op: token.SUB,
+						Y:  &ast.BasicLit{Kind: token.INT, Value: "1"},
+					},
+				},
+			}),
+			&ast.RangeStmt{
+				Key:   ast.NewIdent("_"),
+				Value: ast.NewIdent("record"),
+				Tok:   token.DEFINE,
+				X:     &ast.SliceExpr{X: ast.NewIdent("records"), Low: &ast.BasicLit{Kind: token.INT, Value: "1"}},
+				Body: &ast.BlockStmt{
+					List: []ast.Stmt{
+						defineStmt([]string{"row"}, &ast.CompositeLit{
+							Type: ast.NewIdent("map[string]json.RawMessage"),
+						}),
+						&ast.RangeStmt{
+							Key:   ast.NewIdent("i"),
+							Value: ast.NewIdent("value"),
+							Tok:   token.DEFINE,
+							X:     ast.NewIdent("record"),
+							Body: &ast.BlockStmt{
+								List: []ast.Stmt{
+									&ast.SwitchStmt{
+										Body: &ast.BlockStmt{
+											List: []ast.Stmt{
+												&ast.CaseClause{
+													List: []ast.Expr{&ast.BinaryExpr{
+														X:  ast.NewIdent("value"),
+														Op: token.EQL,
+														Y:  stringLit(""),
+													}},
+												},
+												&ast.CaseClause{
+													List: []ast.Expr{&ast.IndexExpr{
+														X:     ast.NewIdent("raw"),
+														Index: &ast.IndexExpr{X: header, Index: ast.NewIdent("i")},
+													}},
+													Body: []ast.Stmt{
+														&ast.AssignStmt{
+															Lhs: []ast.Expr{rowValue},
+															Tok: token.ASSIGN,
+															Rhs: []ast.Expr{&ast.CallExpr{
+																Fun:  selectorExpr("json", "RawMessage"),
+																Args: []ast.Expr{ast.NewIdent("value")},
+															}},
+														},
+													},
+												},
+												&ast.CaseClause{
+													Body: []ast.Stmt{
+														&ast.AssignStmt{
+															Lhs: []ast.Expr{rowValue, ast.NewIdent("err")},
+															Tok: token.ASSIGN,
+															Rhs: []ast.Expr{&ast.CallExpr{
+																Fun:  selectorExpr("json", "Marshal"),
+																Args: []ast.Expr{ast.NewIdent("value")},
+															}},
+														},
+														ifErrNotNilStmt(),
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+						&ast.AssignStmt{
+							Lhs: []ast.Expr{ast.NewIdent("rows")},
+							Tok: token.ASSIGN,
+							Rhs: []ast.Expr{&ast.CallExpr{
+								Fun:  ast.NewIdent("append"),
+								Args: []ast.Expr{ast.NewIdent("rows"), ast.NewIdent("row")},
+							}},
+						},
+					},
+				},
+			},
+			defineStmt([]string{"data", "err"}, &ast.CallExpr{
+				Fun:  selectorExpr("json", "Marshal"),
+				Args: []ast.Expr{ast.NewIdent("rows")},
+			}),
+			ifErrNotNilStmt(),
+			unmarshallStmt(tokenReceiver, tokenData),
+		},
+	}, importSpecs("os", "encoding/csv", "encoding/json")
+}
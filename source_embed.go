@@ -0,0 +1,146 @@
+package yoitsu
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// NewEmbedSource reads the json from f. The generated LoadData unmarshals a []byte filled by a go:embed
+// directive, and never touches the filesystem.
+//
+// The directive embeds the base name of f, meaning the file must be next to the generated file. See
+// EmbedSourceWithPath to change this
+func NewEmbedSource(name string, f string, opts ...Option[*embedSource]) Source {
+	es := &embedSource{
+		f:         f,
+		name:      name,
+		embedPath: filepath.Base(f),
+	}
+
+	for _, opt := range opts {
+		opt(es)
+	}
+
+	return es
+}
+
+// EmbedSourceWithPath sets the path used in the go:embed directive, relative to the generated file
+func EmbedSourceWithPath(path string) Option[*embedSource] {
+	return func(src *embedSource) {
+		src.embedPath = filepath.ToSlash(path)
+	}
+}
+
+type embedSource struct {
+	f         string
+	b         []byte
+	name      string
+	embedPath string
+}
+
+func (src *embedSource) Json() ([]byte, error) {
+	if src.b != nil {
+		return src.b, nil
+	}
+
+	b, err := os.ReadFile(src.f)
+	if err != nil {
+		return nil, err
+	}
+
+	src.b = b
+	return b, nil
+}
+
+func (src *embedSource) Reader() (io.ReadCloser, error) {
+	return os.Open(src.f)
+}
+
+func (src *embedSource) Name() string {
+	return src.name
+}
+
+func (src *embedSource) varName() string {
+	return "embedded" + toSafeGoName(src.name)
+}
+
+// embedPattern returns embedPath as a go:embed pattern, which is quoted if it contains spaces
+func (src *embedSource) embedPattern() string {
+	if strings.ContainsAny(src.embedPath, " \t") {
+		return strconv.Quote(src.embedPath)
+	}
+	return src.embedPath
+}
+
+// canLoad returns ErrSrcIsNotLoadAble if embedPath can't be used in a go:embed directive. It must be relative to,
+// and inside of the directory of the generated file. Glob characters and characters not allowed in module file
+// names can't be used, and would match other files or fail the build
+func (src *embedSource) canLoad(GeneratedType) error {
+	if src.embedPath == "" || strings.HasPrefix(src.embedPath, "/") || strings.HasSuffix(src.embedPath, "/") {
+		return fmt.Errorf("%w: go:embed path %q must be a relative file path", ErrSrcIsNotLoadAble, src.embedPath)
+	}
+
+	for _, element := range strings.Split(src.embedPath, "/") {
+		if element == "" || element == "." || element == ".." {
+			return fmt.Errorf("%w: go:embed path %q may not contain empty, . or .. elements", ErrSrcIsNotLoadAble, src.embedPath)
+		}
+	}
+
+	if i := strings.IndexFunc(src.embedPath, func(r rune) bool {
+		return r < ' ' || strings.ContainsRune("\"'*<>?[]`|:\\", r)
+	}); i >= 0 {
+		return fmt.Errorf("%w: go:embed path %q contains %q", ErrSrcIsNotLoadAble, src.embedPath, src.embedPath[i])
+	}
+
+	return nil
+}
+
+func (src *embedSource) LoadMethod() (*ast.BlockStmt, []ast.Spec) {
+	return &ast.BlockStmt{
+		List: []ast.Stmt{
+			defineStmt([]string{"data"}, ast.NewIdent(src.varName())),
+			unmarshallStmt(tokenReceiver, tokenData),
+		},
+	}, []ast.Spec{
+		&ast.ImportSpec{
+			Name: ast.NewIdent("_"),
+			Path: &ast.BasicLit{
+				Kind:  token.STRING,
+				Value: strconv.Quote("embed"),
+			},
+		},
+		&ast.ImportSpec{
+			Path: &ast.BasicLit{
+				Kind:  token.STRING,
+				Value: strconv.Quote("encoding/json"),
+			},
+		},
+	}
+}
+
+func (src *embedSource) LoadDecls() []ast.Decl {
+	return []ast.Decl{
+		&ast.GenDecl{
+			Doc: &ast.CommentGroup{
+				List: []*ast.Comment{
+					{
+						Text: "\n//go:embed " + src.embedPattern(),
+					},
+				},
+			},
+			Tok: token.VAR,
+			Specs: []ast.Spec{
+				&ast.ValueSpec{
+					Names: []*ast.Ident{ast.NewIdent(src.varName())},
+					Type:  ast.NewIdent("[]byte"),
+				},
+			},
+		},
+	}
+}
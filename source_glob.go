@@ -0,0 +1,237 @@
+package yoitsu
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+)
+
+// NewGlobSource reads all files matching the pattern, see filepath.Glob. By default, every file is a sample of
+// the root type, see GlobSourceAsArray to change this.
+//
+// The generated LoadData reads the same pattern at runtime. When the files are samples, matches which are
+// JsonArray's are concatenated, so accessors can only be generated if every sample is a JsonArray
+func NewGlobSource(name string, pattern string, opts ...Option[*globSource]) SamplesSource {
+	gs := &globSource{
+		name:    name,
+		pattern: pattern,
+	}
+
+	for _, opt := range opts {
+		opt(gs)
+	}
+
+	return gs
+}
+
+// GlobSourceAsArray treats every file as an element of a synthesized root JsonArray
+func GlobSourceAsArray() Option[*globSource] {
+	return func(src *globSource) {
+		src.asArray = true
+	}
+}
+
+type globSource struct {
+	name    string
+	pattern string
+	asArray bool
+	b       []byte
+}
+
+func (src *globSource) paths() ([]string, error) {
+	paths, err := filepath.Glob(src.pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("%w: no files match %s", ErrNoData, src.pattern)
+	}
+
+	return paths, nil
+}
+
+// Json returns the synthesized JsonArray when GlobSourceAsArray is used, otherwise the samples combined as
+// NewMultiSource does
+func (src *globSource) Json() ([]byte, error) {
+	if src.b != nil {
+		return src.b, nil
+	}
+
+	if !src.asArray {
+		samples, err := src.Samples()
+		if err != nil {
+			return nil, err
+		}
+
+		src.b, err = NewMultiSource(src.name, samples...).Json()
+		return src.b, err
+	}
+
+	paths, err := src.paths()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([][]byte, len(paths))
+	for i, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		// Files are joined as is, so check each one is a single json value
+		b = bytes.TrimSpace(bytes.TrimPrefix(b, []byte("\xEF\xBB\xBF")))
+		if !json.Valid(b) {
+			return nil, fmt.Errorf("%s: invalid json", path)
+		}
+		items[i] = b
+	}
+
+	src.b = append(append([]byte("["), bytes.Join(items, []byte(","))...), ']')
+	return src.b, nil
+}
+
+func (src *globSource) Name() string {
+	return src.name
+}
+
+func (src *globSource) Samples() ([]Source, error) {
+	if src.asArray {
+		return []Source{src}, nil
+	}
+
+	paths, err := src.paths()
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]Source, len(paths))
+	for i, path := range paths {
+		samples[i] = NewFileSource(src.name, path)
+	}
+	return samples, nil
+}
+
+// canLoad returns ErrSrcIsNotLoadAble if the samples aren't JsonArray's, as LoadData concatenates them
+func (src *globSource) canLoad(gType GeneratedType) error {
+	if _, ok := gType.(*SliceType); src.asArray || ok {
+		return nil
+	}
+	return fmt.Errorf("%w: the samples of %s must be json arrays to be loaded, see GlobSourceAsArray", ErrSrcIsNotLoadAble, src.pattern)
+}
+
+func (src *globSource) LoadMethod() (*ast.BlockStmt, []ast.Spec) {
+	// items = append(items, b)
+	appendStmts := []ast.Stmt{&ast.AssignStmt{
+		Lhs: []ast.Expr{ast.NewIdent("items")},
+		Tok: token.ASSIGN,
+		Rhs: []ast.Expr{
+			&ast.CallExpr{
+				Fun:  ast.NewIdent("append"),
+				Args: []ast.Expr{ast.NewIdent("items"), ast.NewIdent("b")},
+			},
+		},
+	}}
+
+	if !src.asArray {
+		// Unwrap every JsonArray, so the samples are concatenated
+		appendStmts = []ast.Stmt{
+			&ast.DeclStmt{
+				Decl: &ast.GenDecl{
+					Tok: token.VAR,
+					Specs: []ast.Spec{
+						&ast.ValueSpec{
+							Names: []*ast.Ident{ast.NewIdent("elems")},
+							Type:  ast.NewIdent("[]json.RawMessage"),
+						},
+					},
+				},
+			},
+			&ast.IfStmt{
+				Cond: &ast.BinaryExpr{
+					X: &ast.CallExpr{
+						Fun: selectorExpr("json", "Unmarshal"),
+						Args: []ast.Expr{
+							ast.NewIdent("b"),
+							&ast.UnaryExpr{Op: token.AND, X: ast.NewIdent("elems")},
+						},
+					},
+					Op: token.NEQ,
+					Y:  ast.NewIdent("nil"),
+				},
+				Body: &ast.BlockStmt{
+					List: []ast.Stmt{
+						&ast.AssignStmt{
+							Lhs: []ast.Expr{ast.NewIdent("elems")},
+							Tok: token.ASSIGN,
+							Rhs: []ast.Expr{
+								&ast.CompositeLit{
+									Type: ast.NewIdent("[]json.RawMessage"),
+									Elts: []ast.Expr{ast.NewIdent("b")},
+								},
+							},
+						},
+					},
+				},
+			},
+			&ast.AssignStmt{
+				Lhs: []ast.Expr{ast.NewIdent("items")},
+				Tok: token.ASSIGN,
+				Rhs: []ast.Expr{
+					&ast.CallExpr{
+						Fun:      ast.NewIdent("append"),
+						Args:     []ast.Expr{ast.NewIdent("items"), ast.NewIdent("elems")},
+						Ellipsis: 1,
+					},
+				},
+			},
+		}
+	}
+
+	loopBody := append([]ast.Stmt{
+		defineStmt([]string{"b", "err"}, &ast.CallExpr{
+			Fun:  selectorExpr("os", "ReadFile"),
+			Args: []ast.Expr{ast.NewIdent("path")},
+		}),
+		ifErrNotNilStmt(),
+	}, appendStmts...)
+
+	return &ast.BlockStmt{
+		List: []ast.Stmt{
+			defineStmt([]string{"paths", "err"}, &ast.CallExpr{
+				Fun:  selectorExpr("filepath", "Glob"),
+				Args: []ast.Expr{stringLit(src.pattern)},
+			}),
+			ifErrNotNilStmt(),
+			&ast.DeclStmt{
+				Decl: &ast.GenDecl{
+					Tok: token.VAR,
+					Specs: []ast.Spec{
+						&ast.ValueSpec{
+							Names: []*ast.Ident{ast.NewIdent("items")},
+							Type:  ast.NewIdent("[]json.RawMessage"),
+						},
+					},
+				},
+			},
+			&ast.RangeStmt{
+				Key:   ast.NewIdent("_"),
+				Value: ast.NewIdent("path"),
+				Tok:   token.DEFINE,
+				X:     ast.NewIdent("paths"),
+				Body:  &ast.BlockStmt{List: loopBody},
+			},
+			defineStmt([]string{"data", "err"}, &ast.CallExpr{
+				Fun:  selectorExpr("json", "Marshal"),
+				Args: []ast.Expr{ast.NewIdent("items")},
+			}),
+			ifErrNotNilStmt(),
+			unmarshallStmt(tokenReceiver, tokenData),
+		},
+	}, importSpecs("os", "path/filepath", "encoding/json")
+}
@@ -0,0 +1,210 @@
+package yoitsu
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// HarEndpoint holds the json responses captured for one method and path template, see ReadHarEndpoints
+type HarEndpoint struct {
+	Method string
+	// Path is the template of the request paths, see HarSourceWithPathTemplate
+	Path string
+	// Name is used for the root type, e.g. GetUsersById for GET /users/{id}
+	Name   string
+	Bodies [][]byte
+}
+
+// Source returns a SamplesSource with every response as a sample, to generate a file per endpoint
+func (e HarEndpoint) Source() SamplesSource {
+	samples := make([]Source, len(e.Bodies))
+	for i, body := range e.Bodies {
+		samples[i] = NewBytesSource(e.Name, body)
+	}
+	return NewMultiSource(e.Name, samples...)
+}
+
+// ReadHarEndpoints reads the HAR file f, and groups all json response bodies by their request method and path
+// template. Endpoints are in the order they were first requested, responses which aren't valid json are skipped
+func ReadHarEndpoints(f string, opts ...Option[*harSource]) ([]HarEndpoint, error) {
+	return newHarSource("", f, opts...).endpoints()
+}
+
+func (src *harSource) endpoints() ([]HarEndpoint, error) {
+	b, err := os.ReadFile(src.f)
+	if err != nil {
+		return nil, err
+	}
+
+	var har harFile
+	if err = json.Unmarshal(b, &har); err != nil {
+		return nil, err
+	}
+
+	var endpoints []HarEndpoint
+	indices := make(map[string]int)
+
+	for _, entry := range har.Log.Entries {
+		content := entry.Response.Content
+		if !strings.Contains(content.MimeType, "json") || content.Text == "" {
+			continue
+		}
+
+		body := []byte(content.Text)
+		if content.Encoding == "base64" {
+			if body, err = base64.StdEncoding.DecodeString(content.Text); err != nil {
+				return nil, fmt.Errorf("%s %s: %w", entry.Request.Method, entry.Request.Url, err)
+			}
+		}
+
+		if !json.Valid(body) {
+			continue
+		}
+
+		u, err := url.Parse(entry.Request.Url)
+		if err != nil {
+			return nil, err
+		}
+
+		method := strings.ToUpper(entry.Request.Method)
+		path := src.pathTemplate(u.Path)
+		key := method + " " + path
+
+		i, ok := indices[key]
+		if !ok {
+			i = len(endpoints)
+			indices[key] = i
+			endpoints = append(endpoints, HarEndpoint{
+				Method: method,
+				Path:   path,
+				Name:   operationName(method, path),
+			})
+		}
+		endpoints[i].Bodies = append(endpoints[i].Bodies, body)
+	}
+
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("%w: no json responses in %s", ErrNoData, src.f)
+	}
+	return endpoints, nil
+}
+
+// NewHarSource generates a root type per endpoint in the HAR file f, named after HarEndpoint.Name, all in one
+// file. Endpoints with only empty responses, like {} or null, are skipped. Use ReadHarEndpoints and
+// HarEndpoint.Source to generate a file per endpoint instead
+func NewHarSource(name string, f string, opts ...Option[*harSource]) Source {
+	return newHarSource(name, f, opts...)
+}
+
+func newHarSource(name string, f string, opts ...Option[*harSource]) *harSource {
+	hs := &harSource{
+		f:            f,
+		name:         name,
+		pathTemplate: defaultHarPathTemplate,
+	}
+
+	for _, opt := range opts {
+		opt(hs)
+	}
+
+	return hs
+}
+
+// HarSourceWithPathTemplate converts the path of a request into the template responses are grouped by.
+//
+// The default replaces segments which are numbers, uuids or long hexadecimal strings with {id}
+func HarSourceWithPathTemplate(template func(path string) string) Option[*harSource] {
+	return func(src *harSource) {
+		src.pathTemplate = template
+	}
+}
+
+type harSource struct {
+	f            string
+	name         string
+	pathTemplate func(path string) string
+}
+
+// harFile is the subset of the HAR format used
+type harFile struct {
+	Log struct {
+		Entries []struct {
+			Request struct {
+				Method string `json:"method"`
+				Url    string `json:"url"`
+			} `json:"request"`
+			Response struct {
+				Content struct {
+					MimeType string `json:"mimeType"`
+					Text     string `json:"text"`
+					Encoding string `json:"encoding"`
+				} `json:"content"`
+			} `json:"response"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+func (src *harSource) GeneratedType(p *Parser) (GeneratedType, error) {
+	endpoints, err := src.endpoints()
+	if err != nil {
+		return nil, err
+	}
+
+	set := newTypeSet()
+	for _, endpoint := range endpoints {
+		roots := make([]JsonObject, len(endpoint.Bodies))
+		for i, body := range endpoint.Bodies {
+			if roots[i], err = decodeJson(body); err != nil {
+				return nil, err
+			}
+		}
+
+		gType, err := p.ParseRoots(endpoint.Name, roots...)
+		if errors.Is(err, ErrNoData) {
+			// e.g. a DELETE answering {}
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", endpoint.Method, endpoint.Path, err)
+		}
+		set.add(toSafeGoName(endpoint.Name), gType)
+	}
+
+	return set, nil
+}
+
+// Root returns nil, the roots are parsed per endpoint
+func (src *harSource) Root() (JsonObject, error) {
+	return nil, nil
+}
+
+// Json returns the HAR file
+func (src *harSource) Json() ([]byte, error) {
+	return os.ReadFile(src.f)
+}
+
+func (src *harSource) Name() string {
+	return src.name
+}
+
+var (
+	uuidRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hexRegex  = regexp.MustCompile(`^[0-9a-fA-F]{16,}$`)
+	intRegex  = regexp.MustCompile(`^[0-9]+$`)
+)
+
+func defaultHarPathTemplate(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if intRegex.MatchString(segment) || uuidRegex.MatchString(segment) || hexRegex.MatchString(segment) {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
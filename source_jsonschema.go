@@ -0,0 +1,102 @@
+package yoitsu
+
+import (
+	"context"
+	"encoding/json"
+	"go/ast"
+	"os"
+)
+
+// NewJsonSchemaSource generates the types described by the JSON Schema (draft 2020-12) in f, instead of inferring
+// them from samples. Supported are the keywords type, properties, required, enum, format, items,
+// additionalProperties, $ref, allOf, anyOf and oneOf. $ref's must point into the same document, a $ref used within
+// the schema it points to must be an object with properties and is emitted as a pointer.
+//
+// Properties not in required are optional fields, see WithOptionalFields. Use OptionalFieldsPointer for required to
+// decide between pointer and value fields, the default OptionalFieldsPlain emits every property as a value. The
+// command and Config default to OptionalFieldsPointer for schemas.
+//
+// Without JsonSchemaSourceWithData, no data is loaded and accessors can't be generated
+func NewJsonSchemaSource(name string, f string, opts ...Option[*jsonSchemaSource]) Source {
+	js := &jsonSchemaSource{
+		f:    f,
+		name: name,
+	}
+
+	for _, opt := range opts {
+		opt(js)
+	}
+
+	if _, ok := js.data.(LoadAbleSource); ok {
+		return &loadAbleJsonSchemaSource{js}
+	}
+	return js
+}
+
+// JsonSchemaSourceWithData sets the Source of the data described by the schema. It is used by the accessors
+func JsonSchemaSourceWithData(data Source) Option[*jsonSchemaSource] {
+	return func(src *jsonSchemaSource) {
+		src.data = data
+	}
+}
+
+type jsonSchemaSource struct {
+	f    string
+	name string
+	data Source
+}
+
+func (src *jsonSchemaSource) GeneratedType(p *Parser) (GeneratedType, error) {
+	b, err := os.ReadFile(src.f)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc interface{}
+	if err = json.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+
+	var schema jsonSchema
+	if err = json.Unmarshal(b, &schema); err != nil {
+		return nil, err
+	}
+
+	gType, _, err := newSchemaBuilder(p, doc).build(src.name, &schema)
+	return gType, err
+}
+
+// Root returns the root of the data, or nil if there is none
+func (src *jsonSchemaSource) Root() (JsonObject, error) {
+	if src.data == nil {
+		return nil, nil
+	}
+	return getRootFromSrc(context.Background(), src.data)
+}
+
+// Json returns the json of the data
+func (src *jsonSchemaSource) Json() ([]byte, error) {
+	if src.data == nil {
+		return nil, ErrNoData
+	}
+	return src.data.Json()
+}
+
+func (src *jsonSchemaSource) Name() string {
+	return src.name
+}
+
+type loadAbleJsonSchemaSource struct {
+	*jsonSchemaSource
+}
+
+func (src *loadAbleJsonSchemaSource) LoadMethod() (*ast.BlockStmt, []ast.Spec) {
+	return src.data.(LoadAbleSource).LoadMethod()
+}
+
+func (src *loadAbleJsonSchemaSource) LoadDecls() []ast.Decl {
+	if declSrc, ok := src.data.(LoadDeclsSource); ok {
+		return declSrc.LoadDecls()
+	}
+	return nil
+}
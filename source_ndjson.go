@@ -0,0 +1,144 @@
+package yoitsu
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/token"
+	"io"
+	"os"
+)
+
+// NewNdjsonSource reads newline delimited json (JSON Lines) from f, every line is an element of the root JsonArray.
+// With WithStreaming the lines are parsed and merged one by one, without reading the whole file into memory. Use
+// WithSampling to only infer from some of the lines. The generated LoadData decodes the file line by line
+func NewNdjsonSource(name string, f string) Source {
+	return &ndjsonSource{
+		f:    f,
+		name: name,
+	}
+}
+
+type ndjsonSource struct {
+	f    string
+	name string
+	root JsonArray
+}
+
+func (src *ndjsonSource) Root() (JsonObject, error) {
+	if src.root != nil {
+		return src.root, nil
+	}
+
+	file, err := os.Open(src.f)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	decoder.UseNumber()
+
+	root := JsonArray{}
+	for decoder.More() {
+		var line interface{}
+		if err = decoder.Decode(&line); err != nil {
+			return nil, err
+		}
+		root = append(root, line)
+	}
+
+	src.root = root
+	return root, nil
+}
+
+// Reader returns the lines as a sequence of json values, see streamValuesSource
+func (src *ndjsonSource) Reader() (io.ReadCloser, error) {
+	return os.Open(src.f)
+}
+
+func (src *ndjsonSource) streamValues() bool {
+	return true
+}
+
+// Json returns the read lines as a json array
+func (src *ndjsonSource) Json() ([]byte, error) {
+	root, err := src.Root()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(root)
+}
+
+func (src *ndjsonSource) Name() string {
+	return src.name
+}
+
+func (src *ndjsonSource) LoadMethod() (*ast.BlockStmt, []ast.Spec) {
+	data := selectorExpr(tokenReceiver, tokenData)
+
+	return &ast.BlockStmt{
+		List: []ast.Stmt{
+			defineStmt([]string{"f", "err"}, &ast.CallExpr{
+				Fun:  selectorExpr("os", "Open"),
+				Args: []ast.Expr{stringLit(src.f)},
+			}),
+			ifErrNotNilStmt(),
+			deferStmt("f", "Close"),
+			&ast.AssignStmt{
+				Lhs: []ast.Expr{data},
+				Tok: token.ASSIGN,
+				Rhs: []ast.Expr{ast.NewIdent("nil")},
+			},
+			defineStmt([]string{"decoder"}, &ast.CallExpr{
+				Fun:  selectorExpr("json", "NewDecoder"),
+				Args: []ast.Expr{ast.NewIdent("f")},
+			}),
+			&ast.ForStmt{
+				Cond: &ast.CallExpr{Fun: selectorExpr("decoder", "More")},
+				Body: &ast.BlockStmt{
+					List: []ast.Stmt{
+						// Grow by one zero element, and decode the line into it
+						defineStmt([]string{"n"}, &ast.CallExpr{
+							Fun:  ast.NewIdent("len"),
+							Args: []ast.Expr{data},
+						}),
+						&ast.AssignStmt{
+							Lhs: []ast.Expr{data},
+							Tok: token.ASSIGN,
+							Rhs: []ast.Expr{
+								&ast.SliceExpr{
+									X: &ast.CallExpr{
+										Fun:  selectorExpr("slices", "Grow"),
+										Args: []ast.Expr{data, &ast.BasicLit{Kind: token.INT, Value: "1"}},
+									},
+									High: &ast.BinaryExpr{
+										X:  ast.NewIdent("n"),
+										Op: token.ADD,
+										Y:  &ast.BasicLit{Kind: token.INT, Value: "1"},
+									},
+								},
+							},
+						},
+						&ast.AssignStmt{
+							Lhs: []ast.Expr{ast.NewIdent("err")},
+							Tok: token.ASSIGN,
+							Rhs: []ast.Expr{
+								&ast.CallExpr{
+									Fun: selectorExpr("decoder", "Decode"),
+									Args: []ast.Expr{
+										&ast.UnaryExpr{
+											Op: token.AND,
+											X:  &ast.IndexExpr{X: data, Index: ast.NewIdent("n")},
+										},
+									},
+								},
+							},
+						},
+						ifErrNotNilStmt(),
+					},
+				},
+			},
+			&ast.ReturnStmt{Results: []ast.Expr{ast.NewIdent("nil")}},
+		},
+	}, importSpecs("os", "slices", "encoding/json")
+}
@@ -0,0 +1,432 @@
+package yoitsu
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// NewOpenApiSource reads the OpenAPI 3 document in f, json or yaml depending on the extension. Every
+// components/schemas entry is generated as a type named after it, see NewJsonSchemaSource for the supported
+// keywords. The component types are added to the Universe, so later generations sharing it reuse them.
+//
+// Responses and request bodies without a schema use their examples as samples.
+// See OpenApiSourceWithClient to generate a client for the operations
+func NewOpenApiSource(name string, f string, opts ...Option[*openApiSource]) Source {
+	oas := &openApiSource{
+		f:    f,
+		name: name,
+	}
+
+	for _, opt := range opts {
+		opt(oas)
+	}
+
+	return oas
+}
+
+// OpenApiSourceWithClient generates a <Name>Client with a method per operation, taking the path parameters,
+// the request body and query parameters, and returning the 2xx json response
+func OpenApiSourceWithClient() Option[*openApiSource] {
+	return func(src *openApiSource) {
+		src.client = true
+	}
+}
+
+type openApiSource struct {
+	f      string
+	name   string
+	client bool
+	b      []byte
+
+	decls   []ast.Decl
+	imports []ast.Spec
+}
+
+// openApiDoc is the subset of an OpenAPI 3 document used to generate types and the client
+type openApiDoc struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas map[string]*jsonSchema `json:"schemas"`
+	} `json:"components"`
+}
+
+type openApiOperationDoc struct {
+	OperationId string                     `json:"operationId"`
+	Summary     string                     `json:"summary"`
+	Parameters  []openApiParameter         `json:"parameters"`
+	RequestBody *openApiRequestBody        `json:"requestBody"`
+	Responses   map[string]openApiResponse `json:"responses"`
+}
+
+type openApiParameter struct {
+	Ref    string      `json:"$ref"`
+	Name   string      `json:"name"`
+	In     string      `json:"in"`
+	Schema *jsonSchema `json:"schema"`
+}
+
+type openApiRequestBody struct {
+	Ref     string                      `json:"$ref"`
+	Content map[string]openApiMediaType `json:"content"`
+}
+
+type openApiResponse struct {
+	Ref     string                      `json:"$ref"`
+	Content map[string]openApiMediaType `json:"content"`
+}
+
+type openApiMediaType struct {
+	Schema   *jsonSchema               `json:"schema"`
+	Example  json.RawMessage           `json:"example"`
+	Examples map[string]openApiExample `json:"examples"`
+}
+
+type openApiExample struct {
+	Ref   string          `json:"$ref"`
+	Value json.RawMessage `json:"value"`
+}
+
+// openApiMethods are the operations of a path item, in the order they're generated
+var openApiMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// openApiOperation is an operation with its types resolved
+type openApiOperation struct {
+	name    string
+	summary string
+	method  string
+	path    string
+	// pathParams are in the order they appear in path
+	pathParams []openApiParam
+	hasQuery   bool
+	// body and result are nil if the operation has no json request body, or 2xx json response
+	body   GeneratedType
+	result GeneratedType
+}
+
+type openApiParam struct {
+	name  string
+	ident string
+	gType GeneratedType
+}
+
+// Json returns the document as json
+func (src *openApiSource) Json() ([]byte, error) {
+	if src.b != nil {
+		return src.b, nil
+	}
+
+	b, err := os.ReadFile(src.f)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(src.f)) {
+	case ".yaml", ".yml":
+		var doc interface{}
+		if err = yaml.Unmarshal(b, &doc); err != nil {
+			return nil, err
+		}
+
+		normalized, err := normalizeTree(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		if b, err = json.Marshal(normalized); err != nil {
+			return nil, err
+		}
+	}
+
+	src.b = b
+	return b, nil
+}
+
+// Root returns nil, the document does not hold any data
+func (src *openApiSource) Root() (JsonObject, error) {
+	return nil, nil
+}
+
+func (src *openApiSource) Name() string {
+	return src.name
+}
+
+func (src *openApiSource) GeneratedType(p *Parser) (GeneratedType, error) {
+	b, err := src.Json()
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := decodeJson(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var spec openApiDoc
+	if err = json.Unmarshal(b, &spec); err != nil {
+		return nil, err
+	}
+
+	builder := newSchemaBuilder(p, doc)
+	set := newTypeSet()
+
+	for _, name := range sortedKeys(spec.Components.Schemas) {
+		escaped := strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+
+		gType, err := builder.resolve("#/components/schemas/" + escaped)
+		if err != nil {
+			return nil, err
+		}
+
+		set.add(toSafeGoName(name), gType)
+		if _, ok := gType.(*StructType); ok {
+			p.yoitsu.universe.AddType(gType)
+		}
+	}
+
+	operations, err := src.operations(p, builder, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, op := range operations {
+		for _, gType := range []GeneratedType{op.body, op.result} {
+			if gType != nil && gType.IsComplexObject() {
+				set.add(gType.Type(), gType)
+			}
+		}
+	}
+
+	if len(set.names) == 0 {
+		return nil, fmt.Errorf("%w: no component schemas or operations with json bodies", ErrNoData)
+	}
+
+	if src.client {
+		src.decls, src.imports = src.clientDecls(operations)
+	}
+
+	return set, nil
+}
+
+// Decls returns the client, see OpenApiSourceWithClient
+func (src *openApiSource) Decls() ([]ast.Decl, []ast.Spec) {
+	return src.decls, src.imports
+}
+
+func (src *openApiSource) operations(p *Parser, builder *schemaBuilder, spec openApiDoc) ([]openApiOperation, error) {
+	var operations []openApiOperation
+
+	for _, path := range sortedKeys(spec.Paths) {
+		item := spec.Paths[path]
+
+		var shared []openApiParameter
+		if raw, ok := item["parameters"]; ok {
+			if err := json.Unmarshal(raw, &shared); err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+		}
+
+		for _, method := range openApiMethods {
+			raw, ok := item[method]
+			if !ok {
+				continue
+			}
+
+			var opDoc openApiOperationDoc
+			if err := json.Unmarshal(raw, &opDoc); err != nil {
+				return nil, fmt.Errorf("%s %s: %w", method, path, err)
+			}
+
+			op, err := src.operation(p, builder, method, path, opDoc, shared)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", method, path, err)
+			}
+			operations = append(operations, op)
+		}
+	}
+
+	return operations, nil
+}
+
+func (src *openApiSource) operation(p *Parser, builder *schemaBuilder, method, path string, opDoc openApiOperationDoc, shared []openApiParameter) (openApiOperation, error) {
+	op := openApiOperation{
+		name:    toSafeGoName(opDoc.OperationId),
+		summary: opDoc.Summary,
+		method:  strings.ToUpper(method),
+		path:    path,
+	}
+
+	if op.name == "" {
+		op.name = operationName(method, path)
+	}
+
+	params := make(map[string]openApiParameter)
+	for _, param := range append(shared, opDoc.Parameters...) {
+		if param.Ref != "" {
+			if err := builder.lookupInto(param.Ref, &param); err != nil {
+				return op, err
+			}
+		}
+
+		switch param.In {
+		case "path":
+			params[param.Name] = param
+		case "query":
+			op.hasQuery = true
+		}
+	}
+
+	for _, match := range pathParamRegex.FindAllStringSubmatch(path, -1) {
+		gType := StringType
+		if param, ok := params[match[1]]; ok {
+			paramType, _, err := builder.build(op.name+match[1], param.Schema)
+			if err != nil {
+				return op, err
+			}
+			if isNumeric(paramType) || paramType.SameType(BoolType, false) {
+				gType = paramType
+			}
+		}
+
+		op.pathParams = append(op.pathParams, openApiParam{
+			name:  match[1],
+			ident: paramIdent(match[1]),
+			gType: gType,
+		})
+	}
+
+	if body := opDoc.RequestBody; body != nil {
+		if body.Ref != "" {
+			if err := builder.lookupInto(body.Ref, body); err != nil {
+				return op, err
+			}
+		}
+
+		var err error
+		if op.body, err = mediaTypeType(p, builder, op.name+"Request", body.Content); err != nil {
+			return op, err
+		}
+	}
+
+	for _, code := range sortedKeys(opDoc.Responses) {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+
+		response := opDoc.Responses[code]
+		if response.Ref != "" {
+			if err := builder.lookupInto(response.Ref, &response); err != nil {
+				return op, err
+			}
+		}
+
+		result, err := mediaTypeType(p, builder, op.name+"Response", response.Content)
+		if err != nil {
+			return op, err
+		}
+
+		if result != nil {
+			op.result = result
+			break
+		}
+	}
+
+	return op, nil
+}
+
+// mediaTypeType returns the type of the json media type in content, using the examples as samples if it has no
+// schema. Nil if there is no json media type, or it can't be typed
+func mediaTypeType(p *Parser, builder *schemaBuilder, name string, content map[string]openApiMediaType) (GeneratedType, error) {
+	var key string
+	for _, k := range sortedKeys(content) {
+		if k == "application/json" || (key == "" && strings.Contains(k, "json")) {
+			key = k
+		}
+	}
+
+	if key == "" {
+		return nil, nil
+	}
+
+	mediaType := content[key]
+	if mediaType.Schema != nil {
+		gType, _, err := builder.build(name, mediaType.Schema)
+		return gType, err
+	}
+
+	samples := []json.RawMessage{mediaType.Example}
+	for _, exampleName := range sortedKeys(mediaType.Examples) {
+		example := mediaType.Examples[exampleName]
+		if example.Ref != "" {
+			if err := builder.lookupInto(example.Ref, &example); err != nil {
+				return nil, err
+			}
+		}
+		samples = append(samples, example.Value)
+	}
+
+	var roots []JsonObject
+	for _, sample := range samples {
+		if len(sample) == 0 {
+			continue
+		}
+
+		root, err := decodeJson(sample)
+		if err != nil {
+			return nil, err
+		}
+		roots = append(roots, root)
+	}
+
+	gType, err := p.ParseRoots(name, roots...)
+	if errors.Is(err, ErrNoData) {
+		return nil, nil
+	}
+	return gType, err
+}
+
+var pathParamRegex = regexp.MustCompile(`\{([^}]+)\}`)
+
+// operationName is used for operations without an operationId, e.g. GetPetsByPetId for GET /pets/{petId}
+func operationName(method, path string) string {
+	name := toSafeGoName(strings.ToLower(method))
+
+	for _, segment := range strings.Split(path, "/") {
+		if match := pathParamRegex.FindStringSubmatch(segment); match != nil {
+			name += "By" + toSafeGoName(match[1])
+			continue
+		}
+		name += toSafeGoName(segment)
+	}
+
+	return name
+}
+
+// reservedIdents are used in the generated client methods, or are imported packages
+var reservedIdents = []string{
+	"c", "ctx", "out", "req", "res", "err", "body", "query", "payload",
+	"bytes", "context", "fmt", "http", "json", "url",
+}
+
+// paramIdent returns a valid, unexported go identifier for a parameter
+func paramIdent(name string) string {
+	ident := []rune(toSafeGoName(name))
+	if len(ident) == 0 {
+		return "param"
+	}
+	ident[0] = []rune(strings.ToLower(string(ident[0])))[0]
+
+	if token.IsKeyword(string(ident)) || slices.Contains(reservedIdents, string(ident)) {
+		return string(ident) + "Param"
+	}
+	return string(ident)
+}
@@ -0,0 +1,302 @@
+package yoitsu
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"slices"
+	"strings"
+)
+
+// clientDecls returns the client struct, its constructor and a method per operation
+func (src *openApiSource) clientDecls(operations []openApiOperation) ([]ast.Decl, []ast.Spec) {
+	clientName := toSafeGoName(src.name) + "Client"
+	imports := []string{"context", "net/http", "fmt"}
+
+	decls := []ast.Decl{
+		&ast.GenDecl{
+			Doc: &ast.CommentGroup{
+				List: []*ast.Comment{
+					{
+						Text: fmt.Sprintf("\n// %s calls the operations of the %s api", clientName, src.name),
+					},
+				},
+			},
+			Tok: token.TYPE,
+			Specs: []ast.Spec{
+				&ast.TypeSpec{
+					Name: ast.NewIdent(clientName),
+					Type: &ast.StructType{
+						Fields: &ast.FieldList{
+							List: []*ast.Field{
+								{
+									Names: []*ast.Ident{ast.NewIdent("BaseURL")},
+									Type:  ast.NewIdent("string"),
+								},
+								{
+									Names: []*ast.Ident{ast.NewIdent("HTTPClient")},
+									Type:  ast.NewIdent("*http.Client"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		&ast.FuncDecl{
+			Doc: &ast.CommentGroup{
+				List: []*ast.Comment{
+					{
+						Text: fmt.Sprintf("\n// New%s returns a %s using http.DefaultClient", clientName, clientName),
+					},
+				},
+			},
+			Name: ast.NewIdent("New" + clientName),
+			Type: &ast.FuncType{
+				Params: &ast.FieldList{
+					List: []*ast.Field{
+						{
+							Names: []*ast.Ident{ast.NewIdent("baseURL")},
+							Type:  ast.NewIdent("string"),
+						},
+					},
+				},
+				Results: &ast.FieldList{
+					List: []*ast.Field{{Type: ast.NewIdent(tokenPointer + clientName)}},
+				},
+			},
+			Body: &ast.BlockStmt{
+				List: []ast.Stmt{
+					&ast.ReturnStmt{
+						Results: []ast.Expr{
+							&ast.UnaryExpr{
+								Op: token.AND,
+								X: &ast.CompositeLit{
+									Type: ast.NewIdent(clientName),
+									Elts: []ast.Expr{
+										&ast.KeyValueExpr{Key: ast.NewIdent("BaseURL"), Value: ast.NewIdent("baseURL")},
+										&ast.KeyValueExpr{Key: ast.NewIdent("HTTPClient"), Value: selectorExpr("http", "DefaultClient")},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, op := range operations {
+		decl, opImports := op.clientMethod(clientName)
+		decls = append(decls, decl)
+
+		for _, i := range opImports {
+			if !slices.Contains(imports, i) {
+				imports = append(imports, i)
+			}
+		}
+	}
+
+	return decls, importSpecs(imports...)
+}
+
+// clientMethod returns the method calling the operation, and the imports it needs besides context, net/http and fmt
+func (op openApiOperation) clientMethod(clientName string) (ast.Decl, []string) {
+	var (
+		imports []string
+		// results are returned before err
+		results []ast.Expr
+		stmts   []ast.Stmt
+	)
+
+	params := []*ast.Field{
+		{
+			Names: []*ast.Ident{ast.NewIdent("ctx")},
+			Type:  selectorExpr("context", "Context"),
+		},
+	}
+	for _, param := range op.pathParams {
+		params = append(params, &ast.Field{
+			Names: []*ast.Ident{ast.NewIdent(param.ident)},
+			Type:  ast.NewIdent(param.gType.Type()),
+		})
+	}
+
+	funcResults := []*ast.Field{{Type: ast.NewIdent(tokenError)}}
+
+	if op.result != nil {
+		imports = append(imports, "encoding/json")
+		results = []ast.Expr{ast.NewIdent("out")}
+		funcResults = append([]*ast.Field{{Type: ast.NewIdent(op.result.Type())}}, funcResults...)
+
+		stmts = append(stmts, &ast.DeclStmt{
+			Decl: &ast.GenDecl{
+				Tok: token.VAR,
+				Specs: []ast.Spec{
+					&ast.ValueSpec{
+						Names: []*ast.Ident{ast.NewIdent("out")},
+						Type:  ast.NewIdent(op.result.Type()),
+					},
+				},
+			},
+		})
+	}
+
+	var reqBody ast.Expr = ast.NewIdent("nil")
+	if op.body != nil {
+		imports = append(imports, "encoding/json", "bytes")
+		params = append(params, &ast.Field{
+			Names: []*ast.Ident{ast.NewIdent("body")},
+			Type:  ast.NewIdent(op.body.Type()),
+		})
+
+		stmts = append(stmts,
+			defineStmt([]string{"payload", "err"}, &ast.CallExpr{
+				Fun:  selectorExpr("json", "Marshal"),
+				Args: []ast.Expr{ast.NewIdent("body")},
+			}),
+			ifErrNotNilStmt(results...),
+		)
+		reqBody = &ast.CallExpr{
+			Fun:  selectorExpr("bytes", "NewReader"),
+			Args: []ast.Expr{ast.NewIdent("payload")},
+		}
+	}
+
+	if op.hasQuery {
+		params = append(params, &ast.Field{
+			Names: []*ast.Ident{ast.NewIdent("query")},
+			Type:  selectorExpr("url", "Values"),
+		})
+	}
+
+	reqUrl, urlImports := op.urlExpr()
+	imports = append(imports, urlImports...)
+	if op.hasQuery && !slices.Contains(imports, "net/url") {
+		imports = append(imports, "net/url")
+	}
+
+	stmts = append(stmts,
+		defineStmt([]string{"req", "err"}, &ast.CallExpr{
+			Fun:  selectorExpr("http", "NewRequestWithContext"),
+			Args: []ast.Expr{ast.NewIdent("ctx"), stringLit(op.method), reqUrl, reqBody},
+		}),
+		ifErrNotNilStmt(results...),
+	)
+
+	setHeader := func(key, value string) {
+		stmts = append(stmts, &ast.ExprStmt{
+			X: &ast.CallExpr{
+				Fun:  selectorExpr("req.Header", "Set"),
+				Args: []ast.Expr{stringLit(key), stringLit(value)},
+			},
+		})
+	}
+	if op.body != nil {
+		setHeader("Content-Type", "application/json")
+	}
+	if op.result != nil {
+		setHeader("Accept", "application/json")
+	}
+
+	if op.hasQuery {
+		stmts = append(stmts, &ast.AssignStmt{
+			Lhs: []ast.Expr{selectorExpr("req.URL", "RawQuery")},
+			Tok: token.ASSIGN,
+			Rhs: []ast.Expr{&ast.CallExpr{Fun: selectorExpr("query", "Encode")}},
+		})
+	}
+
+	stmts = append(stmts,
+		defineStmt([]string{"res", "err"}, &ast.CallExpr{
+			Fun:  selectorExpr("c.HTTPClient", "Do"),
+			Args: []ast.Expr{ast.NewIdent("req")},
+		}),
+		ifErrNotNilStmt(results...),
+		deferStmt("res.Body", "Close"),
+		statusCheckStmt(fmt.Sprintf("%s %s: unexpected status %%s", op.method, strings.ReplaceAll(op.path, "%", "%%")), results...),
+	)
+
+	if op.result != nil {
+		stmts = append(stmts,
+			&ast.AssignStmt{
+				Lhs: []ast.Expr{ast.NewIdent("err")},
+				Tok: token.ASSIGN,
+				Rhs: []ast.Expr{&ast.CallExpr{
+					Fun: &ast.SelectorExpr{
+						X: &ast.CallExpr{
+							Fun:  selectorExpr("json", "NewDecoder"),
+							Args: []ast.Expr{selectorExpr("res", "Body")},
+						},
+						Sel: ast.NewIdent("Decode"),
+					},
+					Args: []ast.Expr{&ast.UnaryExpr{Op: token.AND, X: ast.NewIdent("out")}},
+				}},
+			},
+			&ast.ReturnStmt{Results: append(results, ast.NewIdent("err"))},
+		)
+	} else {
+		stmts = append(stmts, &ast.ReturnStmt{Results: []ast.Expr{ast.NewIdent("nil")}})
+	}
+
+	doc := fmt.Sprintf("\n// %s calls %s %s", op.name, op.method, op.path)
+	if op.summary != "" {
+		doc += "\n//\n// " + strings.ReplaceAll(strings.TrimSpace(op.summary), "\n", "\n// ")
+	}
+
+	return &ast.FuncDecl{
+		Doc: &ast.CommentGroup{
+			List: []*ast.Comment{{Text: doc}},
+		},
+		Recv: &ast.FieldList{
+			List: []*ast.Field{
+				{
+					Names: []*ast.Ident{ast.NewIdent("c")},
+					Type:  ast.NewIdent(tokenPointer + clientName),
+				},
+			},
+		},
+		Name: ast.NewIdent(op.name),
+		Type: &ast.FuncType{
+			Params:  &ast.FieldList{List: params},
+			Results: &ast.FieldList{List: funcResults},
+		},
+		Body: &ast.BlockStmt{List: stmts},
+	}, imports
+}
+
+// urlExpr returns c.BaseURL joined with the path, escaping the path parameters
+func (op openApiOperation) urlExpr() (ast.Expr, []string) {
+	var (
+		imports []string
+		expr    ast.Expr = selectorExpr("c", "BaseURL")
+	)
+
+	add := func(y ast.Expr) {
+		expr = &ast.BinaryExpr{X: expr, Op: token.ADD, Y: y}
+	}
+
+	rest := op.path
+	for _, param := range op.pathParams {
+		placeholder := "{" + param.name + "}"
+		i := strings.Index(rest, placeholder)
+
+		if i > 0 {
+			add(stringLit(rest[:i]))
+		}
+		rest = rest[i+len(placeholder):]
+
+		var value ast.Expr = ast.NewIdent(param.ident)
+		if !param.gType.SameType(StringType, false) {
+			value = &ast.CallExpr{Fun: selectorExpr("fmt", "Sprint"), Args: []ast.Expr{value}}
+		}
+		add(&ast.CallExpr{Fun: selectorExpr("url", "PathEscape"), Args: []ast.Expr{value}})
+		imports = []string{"net/url"}
+	}
+
+	if rest != "" {
+		add(stringLit(rest))
+	}
+
+	return expr, imports
+}
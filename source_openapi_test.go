@@ -0,0 +1,106 @@
+package yoitsu
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+)
+
+const petsSpec = `{
+	"openapi": "3.0.0",
+	"info": {"title": "pets", "version": "1"},
+	"paths": {
+		"/pets/{petId}": {
+			"parameters": [{"name": "petId", "in": "path", "required": true, "schema": {"type": "integer", "format": "int64"}}],
+			"get": {
+				"operationId": "getPet",
+				"responses": {"200": {"description": "ok", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}}
+			},
+			"delete": {"responses": {"204": {"description": "deleted"}}}
+		},
+		"/pets": {
+			"get": {
+				"parameters": [{"name": "limit", "in": "query", "schema": {"type": "integer"}}],
+				"responses": {"200": {"description": "ok", "content": {"application/json": {"example": [{"id": 1, "name": "a"}]}}}}
+			},
+			"post": {
+				"operationId": "createPet",
+				"requestBody": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/NewPet"}}}},
+				"responses": {"201": {"description": "created", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}}
+			}
+		}
+	},
+	"components": {
+		"schemas": {
+			"NewPet": {"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}, "tag": {"type": "string"}}},
+			"Pet": {"allOf": [{"$ref": "#/components/schemas/NewPet"}, {"type": "object", "required": ["id"], "properties": {"id": {"type": "integer", "format": "int64"}}}]}
+		}
+	}
+}`
+
+func TestOpenApiClient(t *testing.T) {
+	f := writeTemp(t, "pets.json", petsSpec)
+	y := New(NewOpenApiSource("Pets", f, OpenApiSourceWithClient()), WithOptionalFields(OptionalFieldsPointer))
+	got := generateDecls(t, y)
+
+	checkDecls(t, got, map[string]string{
+		"Pet":                 "type Pet struct { Id int64 `json:\"id\"` Name string `json:\"name\"` Tag *string `json:\"tag,omitempty\"` }",
+		"NewPet":              "type NewPet struct { Name string `json:\"name\"` Tag *string `json:\"tag,omitempty\"` }",
+		"GetPetsResponseItem": "type GetPetsResponseItem struct { Id int `json:\"id\"` Name string `json:\"name\"` }",
+		"PetsClient":          "type PetsClient struct { BaseURL string HTTPClient *http.Client }",
+		"NewPetsClient":       "func NewPetsClient(baseURL string) *PetsClient { return &PetsClient{BaseURL: baseURL, HTTPClient: http.DefaultClient} }",
+		"PetsClient.GetPet": "func (c *PetsClient) GetPet(ctx context.Context, petId int64) (Pet, error) { var out Pet " +
+			"req, err := http.NewRequestWithContext(ctx, \"GET\", c.BaseURL+\"/pets/\"+url.PathEscape(fmt.Sprint(petId)), nil) " +
+			"if err != nil { return out, err } req.Header.Set(\"Accept\", \"application/json\") " +
+			"res, err := c.HTTPClient.Do(req) if err != nil { return out, err } defer res.Body.Close() " +
+			"if res.StatusCode < 200 || res.StatusCode > 299 { return out, fmt.Errorf(\"GET /pets/{petId}: unexpected status %s\", res.Status) } " +
+			"err = json.NewDecoder(res.Body).Decode(&out) return out, err }",
+	})
+
+	signatures := map[string]string{
+		"PetsClient.GetPets":           "func (c *PetsClient) GetPets(ctx context.Context, query url.Values) ([]GetPetsResponseItem, error) {",
+		"PetsClient.CreatePet":         "func (c *PetsClient) CreatePet(ctx context.Context, body NewPet) (Pet, error) {",
+		"PetsClient.DeletePetsByPetId": "func (c *PetsClient) DeletePetsByPetId(ctx context.Context, petId int64) error {",
+	}
+	for name, signature := range signatures {
+		if !strings.HasPrefix(got[name], signature) {
+			t.Errorf("%s:\ngot  %s\nwant %s ...", name, got[name], signature)
+		}
+	}
+
+	if !strings.Contains(got["PetsClient.CreatePet"], `req.Header.Set("Content-Type", "application/json")`) {
+		t.Errorf("CreatePet doesn't set the Content-Type: %s", got["PetsClient.CreatePet"])
+	}
+	if !strings.Contains(got["PetsClient.GetPets"], "req.URL.RawQuery = query.Encode()") {
+		t.Errorf("GetPets doesn't encode the query: %s", got["PetsClient.GetPets"])
+	}
+
+	typeCheck(t, y)
+}
+
+// typeCheck formats the generated file, and fails the test if it doesn't compile
+func typeCheck(t *testing.T, y *Yoitsu) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, token.NewFileSet(), y.File); err != nil {
+		t.Fatal(err)
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "generated.go", buf.Bytes(), 0)
+	if err != nil {
+		t.Fatalf("%v\n%s", err, buf.String())
+	}
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err = conf.Check("generated", fset, []*ast.File{file}, nil); err != nil {
+		t.Errorf("%v\n%s", err, buf.String())
+	}
+}
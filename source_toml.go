@@ -0,0 +1,64 @@
+package yoitsu
+
+import (
+	"encoding/json"
+	"go/ast"
+
+	"github.com/BurntSushi/toml"
+)
+
+// NewTomlSource reads toml from f. Datetimes become TimeType, fields are tagged with both json and toml, and the
+// generated LoadData decodes toml
+func NewTomlSource(name string, f string) Source {
+	return &tomlSource{
+		f:    f,
+		name: name,
+	}
+}
+
+type tomlSource struct {
+	f    string
+	name string
+	root JsonObject
+}
+
+func (src *tomlSource) Root() (JsonObject, error) {
+	if src.root != nil {
+		return src.root, nil
+	}
+
+	var root map[string]interface{}
+	if _, err := toml.DecodeFile(src.f, &root); err != nil {
+		return nil, err
+	}
+
+	var err error
+	src.root, err = normalizeTree(root)
+	if err != nil {
+		return nil, err
+	}
+	return src.root, nil
+}
+
+// Json returns the toml converted to json
+func (src *tomlSource) Json() ([]byte, error) {
+	root, err := src.Root()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(root)
+}
+
+func (src *tomlSource) Name() string {
+	return src.name
+}
+
+func (src *tomlSource) StructTags() []string {
+	return []string{"toml"}
+}
+
+func (src *tomlSource) LoadMethod() (*ast.BlockStmt, []ast.Spec) {
+	return &ast.BlockStmt{
+		List: append(readFileStmts(src.f), unmarshallWithStmt("toml", tokenReceiver, tokenData)),
+	}, importSpecs("os", "github.com/BurntSushi/toml")
+}
@@ -0,0 +1,382 @@
+package yoitsu
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+func NewUrlSource(name string, u string, opts ...Option[*urlSource]) Source {
+	us := &urlSource{
+		url:     u,
+		name:    name,
+		method:  http.MethodGet,
+		headers: make(map[string]string),
+	}
+
+	for _, opt := range opts {
+		opt(us)
+	}
+
+	if us.httpClient == nil {
+		us.httpClient = http.DefaultClient
+	}
+
+	return us
+}
+
+type urlSource struct {
+	httpClient *http.Client
+	url        string
+	b          []byte
+	name       string
+
+	method string
+	body   []byte
+	// headers are set as is, envHeaders are read from the environment variable when making the request
+	headers    map[string]string
+	envHeaders map[string]string
+
+	bearerEnv    string
+	basicUserEnv string
+	basicPassEnv string
+
+	// timeout is per attempt, retries are made with an exponential backoff starting at backoff
+	timeout time.Duration
+	retries int
+	backoff time.Duration
+
+	// cacheDir is empty if caching is disabled, see UrlSourceWithCache
+	cacheDir string
+}
+
+func (src *urlSource) newRequest(ctx context.Context) (*http.Request, error) {
+	var body io.Reader
+	if src.body != nil {
+		body = bytes.NewReader(src.body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, src.method, src.url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range sortedKeys(src.headers) {
+		req.Header.Set(key, src.headers[key])
+	}
+
+	for _, key := range sortedKeys(src.envHeaders) {
+		req.Header.Set(key, os.Getenv(src.envHeaders[key]))
+	}
+
+	if src.bearerEnv != "" {
+		req.Header.Set("Authorization", "Bearer "+os.Getenv(src.bearerEnv))
+	}
+
+	if src.basicUserEnv != "" {
+		req.SetBasicAuth(os.Getenv(src.basicUserEnv), os.Getenv(src.basicPassEnv))
+	}
+
+	return req, nil
+}
+
+func (src *urlSource) Json() ([]byte, error) {
+	return src.JsonContext(context.Background())
+}
+
+// JsonContext makes the request, retrying on network errors, 429 and 5xx responses if configured.
+// See UrlSourceWithRetries
+func (src *urlSource) JsonContext(ctx context.Context) ([]byte, error) {
+	if src.b != nil {
+		return src.b, nil
+	}
+
+	parsedUrl, err := url.Parse(src.url)
+	if err != nil {
+		return nil, err
+	}
+
+	src.url = parsedUrl.String()
+	cached := src.readCache()
+
+	for attempt := 0; ; attempt++ {
+		var (
+			b     []byte
+			retry bool
+		)
+
+		b, retry, err = src.attempt(ctx, cached)
+		if err == nil {
+			src.b = b
+			return b, nil
+		}
+
+		if !retry || attempt >= src.retries {
+			// Work offline if the server couldn't be reached
+			if cached != nil && ctx.Err() == nil && !errors.Is(err, ErrUnexpectedStatus) {
+				src.b = cached.body
+				return cached.body, nil
+			}
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(src.backoff << attempt):
+		}
+	}
+}
+
+// attempt makes a single request, retry is true if the error may be resolved by trying again
+func (src *urlSource) attempt(ctx context.Context, cached *urlCacheEntry) (b []byte, retry bool, err error) {
+	attemptCtx := ctx
+	if src.timeout > 0 {
+		var cancel context.CancelFunc
+		attemptCtx, cancel = context.WithTimeout(ctx, src.timeout)
+		defer cancel()
+	}
+
+	req, err := src.newRequest(attemptCtx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if cached != nil {
+		cached.setConditionalHeaders(req)
+	}
+
+	resp, err := src.httpClient.Do(req)
+	if err != nil {
+		return nil, ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		return cached.body, false, nil
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		retry = resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return nil, retry, fmt.Errorf("%w: %s %s: %s", ErrUnexpectedStatus, src.method, src.url, resp.Status)
+	}
+
+	b, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, ctx.Err() == nil, err
+	}
+
+	// The response is still used if it can't be cached
+	_ = src.writeCache(resp.Header, b)
+	return b, false, nil
+}
+
+func (src *urlSource) Name() string {
+	return src.name
+}
+
+func (src *urlSource) LoadMethod() (*ast.BlockStmt, []ast.Spec) {
+	imports := []string{"net/http", "io", "encoding/json", "fmt"}
+
+	var body ast.Expr = ast.NewIdent("nil")
+	if src.body != nil {
+		body = &ast.CallExpr{
+			Fun:  selectorExpr("strings", "NewReader"),
+			Args: []ast.Expr{embeddedLit(src.body)},
+		}
+		imports = append(imports, "strings")
+	}
+
+	stmts := []ast.Stmt{
+		defineStmt([]string{"req", "err"}, &ast.CallExpr{
+			Fun:  selectorExpr("http", "NewRequest"),
+			Args: []ast.Expr{stringLit(src.method), stringLit(src.url), body},
+		}),
+		ifErrNotNilStmt(),
+	}
+
+	setHeader := func(key string, value ast.Expr) {
+		stmts = append(stmts, &ast.ExprStmt{
+			X: &ast.CallExpr{
+				Fun:  selectorExpr("req.Header", "Set"),
+				Args: []ast.Expr{stringLit(key), value},
+			},
+		})
+	}
+
+	getEnv := func(env string) ast.Expr {
+		return &ast.CallExpr{
+			Fun:  selectorExpr("os", "Getenv"),
+			Args: []ast.Expr{stringLit(env)},
+		}
+	}
+
+	for _, key := range sortedKeys(src.headers) {
+		setHeader(key, stringLit(src.headers[key]))
+	}
+
+	for _, key := range sortedKeys(src.envHeaders) {
+		setHeader(key, getEnv(src.envHeaders[key]))
+	}
+
+	if src.bearerEnv != "" {
+		setHeader("Authorization", &ast.BinaryExpr{
+			X:  stringLit("Bearer "),
+			Op: token.ADD,
+			Y:  getEnv(src.bearerEnv),
+		})
+	}
+
+	if src.basicUserEnv != "" {
+		stmts = append(stmts, &ast.ExprStmt{
+			X: &ast.CallExpr{
+				Fun:  selectorExpr("req", "SetBasicAuth"),
+				Args: []ast.Expr{getEnv(src.basicUserEnv), getEnv(src.basicPassEnv)},
+			},
+		})
+	}
+
+	if len(src.envHeaders) > 0 || src.bearerEnv != "" || src.basicUserEnv != "" {
+		imports = append(imports, "os")
+	}
+
+	stmts = append(stmts,
+		defineStmt([]string{"res", "err"}, &ast.CallExpr{
+			Fun:  selectorExpr("http.DefaultClient", "Do"),
+			Args: []ast.Expr{ast.NewIdent("req")},
+		}),
+		ifErrNotNilStmt(),
+		deferStmt("res.Body", "Close"),
+		statusCheckStmt(fmt.Sprintf("%s %s: unexpected status %%s", src.method, strings.ReplaceAll(src.url, "%", "%%"))),
+		defineStmt([]string{"data", "err"}, &ast.CallExpr{
+			Fun:  selectorExpr("io", "ReadAll"),
+			Args: []ast.Expr{ast.NewIdent("res.Body")},
+		}),
+		ifErrNotNilStmt(),
+		unmarshallStmt(tokenReceiver, tokenData),
+	)
+
+	return &ast.BlockStmt{List: stmts}, importSpecs(imports...)
+}
+
+// statusCheckStmt returns an error formatted with res.Status if res.StatusCode is not 2xx, after results
+func statusCheckStmt(format string, results ...ast.Expr) ast.Stmt {
+	return &ast.IfStmt{
+		Cond: &ast.BinaryExpr{
+			X: &ast.BinaryExpr{
+				X:  selectorExpr("res", "StatusCode"),
+				Op: token.LSS,
+				Y:  &ast.BasicLit{Kind: token.INT, Value: "200"},
+			},
+			Op: token.LOR,
+			Y: &ast.BinaryExpr{
+				X:  selectorExpr("res", "StatusCode"),
+				Op: token.GTR,
+				Y:  &ast.BasicLit{Kind: token.INT, Value: "299"},
+			},
+		},
+		Body: &ast.BlockStmt{
+			List: []ast.Stmt{
+				&ast.ReturnStmt{
+					Results: append(results, &ast.CallExpr{
+						Fun:  selectorExpr("fmt", "Errorf"),
+						Args: []ast.Expr{stringLit(format), selectorExpr("res", "Status")},
+					}),
+				},
+			},
+		},
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func UrlSourceWithHttpClient(c *http.Client) Option[*urlSource] {
+	return func(source *urlSource) {
+		source.httpClient = c
+	}
+}
+
+// UrlSourceWithTimeout sets the timeout of a single attempt, see UrlSourceWithRetries
+func UrlSourceWithTimeout(timeout time.Duration) Option[*urlSource] {
+	return func(source *urlSource) {
+		source.timeout = timeout
+	}
+}
+
+// UrlSourceWithRetries retries failed requests up to retries times, waiting backoff, 2*backoff, 4*backoff, ...
+// between attempts. Only network errors, 429 and 5xx responses are retried
+func UrlSourceWithRetries(retries int, backoff time.Duration) Option[*urlSource] {
+	return func(source *urlSource) {
+		source.retries = retries
+		source.backoff = backoff
+	}
+}
+
+// UrlSourceWithMethod sets the http method, defaults to GET
+func UrlSourceWithMethod(method string) Option[*urlSource] {
+	return func(source *urlSource) {
+		source.method = method
+	}
+}
+
+// UrlSourceWithBody sends the body with the request, and switches the method to POST if it was GET.
+// Remember to set the Content-Type with UrlSourceWithHeader
+func UrlSourceWithBody(body []byte) Option[*urlSource] {
+	return func(source *urlSource) {
+		source.body = body
+		if source.method == http.MethodGet {
+			source.method = http.MethodPost
+		}
+	}
+}
+
+// UrlSourceWithHeader sets a header, the value is included in the generated file as is.
+// Use UrlSourceWithEnvHeader for secrets
+func UrlSourceWithHeader(key, value string) Option[*urlSource] {
+	return func(source *urlSource) {
+		source.headers[key] = value
+	}
+}
+
+// UrlSourceWithEnvHeader sets a header to the value of the environment variable, the generated file reads the same
+// environment variable
+func UrlSourceWithEnvHeader(key, env string) Option[*urlSource] {
+	return func(source *urlSource) {
+		if source.envHeaders == nil {
+			source.envHeaders = make(map[string]string)
+		}
+		source.envHeaders[key] = env
+	}
+}
+
+// UrlSourceWithBearerEnv sets the Authorization header to a bearer token read from the environment variable
+func UrlSourceWithBearerEnv(env string) Option[*urlSource] {
+	return func(source *urlSource) {
+		source.bearerEnv = env
+	}
+}
+
+// UrlSourceWithBasicAuthEnv sets basic auth with the username and password read from the environment variables
+func UrlSourceWithBasicAuthEnv(userEnv, passEnv string) Option[*urlSource] {
+	return func(source *urlSource) {
+		source.basicUserEnv = userEnv
+		source.basicPassEnv = passEnv
+	}
+}
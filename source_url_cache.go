@@ -0,0 +1,131 @@
+package yoitsu
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// UrlSourceWithCache stores responses in dir, keyed by method, url and body. Cached responses are revalidated with
+// conditional requests, and used as is when the request fails because of a network error. Failing to write to the
+// cache doesn't fail the request
+func UrlSourceWithCache(dir string) Option[*urlSource] {
+	return func(source *urlSource) {
+		source.cacheDir = dir
+	}
+}
+
+// urlCacheEntry is stored next to the cached body as <key>.meta.json
+type urlCacheEntry struct {
+	Url          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+
+	body []byte
+}
+
+func (src *urlSource) cacheKey() string {
+	h := sha256.New()
+	h.Write([]byte(src.method + " " + src.url + "\n"))
+	h.Write(src.body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (src *urlSource) cachePaths() (body string, meta string) {
+	key := src.cacheKey()
+	return filepath.Join(src.cacheDir, key+".json"), filepath.Join(src.cacheDir, key+".meta.json")
+}
+
+// readCache returns nil if caching is disabled, or no usable entry exists
+func (src *urlSource) readCache() *urlCacheEntry {
+	if src.cacheDir == "" {
+		return nil
+	}
+
+	bodyPath, metaPath := src.cachePaths()
+
+	metaBytes, err := os.ReadFile(metaPath)
+	if err != nil {
+		return nil
+	}
+
+	var entry urlCacheEntry
+	if err = json.Unmarshal(metaBytes, &entry); err != nil {
+		return nil
+	}
+
+	entry.body, err = os.ReadFile(bodyPath)
+	if err != nil {
+		return nil
+	}
+
+	return &entry
+}
+
+// writeCache stores the response. Files are replaced atomically, so a concurrent or interrupted write never leaves a
+// partial entry behind
+func (src *urlSource) writeCache(header http.Header, body []byte) error {
+	if src.cacheDir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(src.cacheDir, 0o755); err != nil {
+		return err
+	}
+
+	metaBytes, err := json.Marshal(urlCacheEntry{
+		Url:          src.url,
+		ETag:         header.Get("ETag"),
+		LastModified: header.Get("Last-Modified"),
+	})
+	if err != nil {
+		return err
+	}
+
+	bodyPath, metaPath := src.cachePaths()
+
+	// Without the meta file the entry isn't used, so the old meta never describes the new body
+	if err = os.Remove(metaPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err = writeFileAtomic(bodyPath, body); err != nil {
+		return err
+	}
+	return writeFileAtomic(metaPath, metaBytes)
+}
+
+// writeFileAtomic writes data to a temporary file next to path, and renames it into place
+func writeFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err = f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Chmod(0o644); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// setConditionalHeaders lets the server answer with 304 Not Modified if the cached entry is still valid
+func (entry *urlCacheEntry) setConditionalHeaders(req *http.Request) {
+	if entry.ETag != "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+	if entry.LastModified != "" {
+		req.Header.Set("If-Modified-Since", entry.LastModified)
+	}
+}
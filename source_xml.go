@@ -0,0 +1,266 @@
+package yoitsu
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"go/ast"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	// xmlAttrPrefix is prepended to the keys of attributes
+	xmlAttrPrefix = "@"
+	// xmlTextPrefix starts the key of the character data of elements with attributes or children
+	xmlTextPrefix = "#"
+	xmlTextKey    = xmlTextPrefix + "text"
+)
+
+// NewXmlSource reads xml from f. The root element becomes the root type, attributes are keys prefixed with @ and
+// elements repeated anywhere in the document become a JsonArray. Character data of elements which also have
+// attributes or children is kept under #text.
+//
+// Like NewCsvSource, values are a bool or a number if they all are for the same element or attribute. Fields are
+// tagged with both json and xml, and the generated LoadData decodes xml
+func NewXmlSource(name string, f string) Source {
+	return &xmlSource{
+		f:    f,
+		name: name,
+	}
+}
+
+type xmlSource struct {
+	f    string
+	name string
+	root JsonObject
+}
+
+type xmlNode struct {
+	name     string
+	attrs    []xml.Attr
+	children []*xmlNode
+	text     string
+}
+
+func (n *xmlNode) complex() bool {
+	return len(n.attrs) > 0 || len(n.children) > 0
+}
+
+// xmlTree holds what is known about every path in the document, paths are element names joined by a /
+type xmlTree struct {
+	// complex paths have attributes or children in at least one element
+	complex map[string]bool
+	// repeated paths occur more than once in at least one parent
+	repeated map[string]bool
+	values   map[string][]string
+	kinds    map[string]string
+}
+
+func (src *xmlSource) Root() (JsonObject, error) {
+	if src.root != nil {
+		return src.root, nil
+	}
+
+	file, err := os.Open(src.f)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	root, err := parseXmlNodes(xml.NewDecoder(file))
+	if err != nil {
+		return nil, err
+	}
+
+	tree := &xmlTree{
+		complex:  make(map[string]bool),
+		repeated: make(map[string]bool),
+		values:   make(map[string][]string),
+		kinds:    make(map[string]string),
+	}
+	tree.analyze(root, root.name)
+	for path := range tree.complex {
+		// Character data of elements without attributes or children, at a path where others have them
+		textPath := path + "/" + xmlTextKey
+		tree.values[textPath] = append(tree.values[textPath], tree.values[path]...)
+		delete(tree.values, path)
+	}
+	for path, values := range tree.values {
+		tree.kinds[path] = rawKind(values)
+	}
+
+	src.root = tree.build(root, root.name)
+	return src.root, nil
+}
+
+// parseXmlNodes returns the root element, whitespace around character data is trimmed
+func parseXmlNodes(decoder *xml.Decoder) (*xmlNode, error) {
+	var (
+		stack []*xmlNode
+		root  *xmlNode
+	)
+
+	for {
+		tok, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			node := &xmlNode{name: t.Name.Local}
+			for _, attr := range t.Attr {
+				if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+					continue
+				}
+				node.attrs = append(node.attrs, attr)
+			}
+
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			} else {
+				root = node
+			}
+			stack = append(stack, node)
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(t)
+			}
+		case xml.EndElement:
+			node := stack[len(stack)-1]
+			node.text = strings.TrimSpace(node.text)
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	if root == nil {
+		return nil, fmt.Errorf("%w: xml has no root element", ErrNoData)
+	}
+	return root, nil
+}
+
+func (t *xmlTree) analyze(node *xmlNode, path string) {
+	if !node.complex() {
+		t.values[path] = append(t.values[path], node.text)
+		return
+	}
+	t.complex[path] = true
+
+	for _, attr := range node.attrs {
+		attrPath := path + "/" + xmlAttrPrefix + attr.Name.Local
+		t.values[attrPath] = append(t.values[attrPath], attr.Value)
+	}
+
+	if node.text != "" {
+		t.values[path+"/"+xmlTextKey] = append(t.values[path+"/"+xmlTextKey], node.text)
+	}
+
+	seen := make(map[string]bool)
+	for _, child := range node.children {
+		childPath := path + "/" + child.name
+		if seen[child.name] {
+			t.repeated[childPath] = true
+		}
+		seen[child.name] = true
+
+		t.analyze(child, childPath)
+	}
+}
+
+func (t *xmlTree) build(node *xmlNode, path string) JsonObject {
+	if !t.complex[path] {
+		return t.value(path, node.text)
+	}
+
+	m := make(JsonMap)
+	for _, attr := range node.attrs {
+		key := xmlAttrPrefix + attr.Name.Local
+		m[key] = t.value(path+"/"+key, attr.Value)
+	}
+
+	if node.text != "" {
+		m[xmlTextKey] = t.value(path+"/"+xmlTextKey, node.text)
+	}
+
+	for _, child := range node.children {
+		childPath := path + "/" + child.name
+		value := t.build(child, childPath)
+
+		if !t.repeated[childPath] {
+			m[child.name] = value
+			continue
+		}
+
+		array, _ := m[child.name].(JsonArray)
+		m[child.name] = append(array, value)
+	}
+
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
+
+// value converts s into the kind of the path, see rawKind
+func (t *xmlTree) value(path string, s string) JsonObject {
+	kind := t.kinds[path]
+
+	switch {
+	case s == "" && kind != "":
+		return nil
+	case kind == "bool":
+		return s == "true"
+	case kind == "number":
+		return json.Number(s)
+	}
+	return s
+}
+
+// Json returns the xml converted to json
+func (src *xmlSource) Json() ([]byte, error) {
+	root, err := src.Root()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(root)
+}
+
+func (src *xmlSource) Name() string {
+	return src.name
+}
+
+func (src *xmlSource) StructTags() []string {
+	return []string{"xml"}
+}
+
+func (src *xmlSource) LoadMethod() (*ast.BlockStmt, []ast.Spec) {
+	return &ast.BlockStmt{
+		List: append(readFileStmts(src.f), unmarshallWithStmt("xml", tokenReceiver, tokenData)),
+	}, importSpecs("os", "encoding/xml")
+}
+
+// xmlTagValue converts the value of a json tag into the value of the xml tag
+func xmlTagValue(value string) string {
+	name, options, _ := strings.Cut(value, ",")
+
+	switch {
+	case strings.HasPrefix(name, xmlAttrPrefix):
+		value = strings.TrimPrefix(name, xmlAttrPrefix) + ",attr"
+	case name == xmlTextKey:
+		return ",chardata"
+	default:
+		value = name
+	}
+
+	if options != "" {
+		value += "," + options
+	}
+	return value
+}
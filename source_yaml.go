@@ -0,0 +1,137 @@
+package yoitsu
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// NewYamlSource reads yaml from f. Fields are tagged with both json and yaml, and the generated LoadData
+// decodes yaml
+func NewYamlSource(name string, f string) Source {
+	return &yamlSource{
+		f:    f,
+		name: name,
+	}
+}
+
+type yamlSource struct {
+	f    string
+	name string
+	root JsonObject
+}
+
+func (src *yamlSource) Root() (JsonObject, error) {
+	if src.root != nil {
+		return src.root, nil
+	}
+
+	b, err := os.ReadFile(src.f)
+	if err != nil {
+		return nil, err
+	}
+
+	var root interface{}
+	if err = yaml.Unmarshal(b, &root); err != nil {
+		return nil, err
+	}
+
+	src.root, err = normalizeTree(root)
+	if err != nil {
+		return nil, err
+	}
+	return src.root, nil
+}
+
+// Json returns the yaml converted to json
+func (src *yamlSource) Json() ([]byte, error) {
+	root, err := src.Root()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(root)
+}
+
+func (src *yamlSource) Name() string {
+	return src.name
+}
+
+func (src *yamlSource) StructTags() []string {
+	return []string{"yaml"}
+}
+
+func (src *yamlSource) LoadMethod() (*ast.BlockStmt, []ast.Spec) {
+	return &ast.BlockStmt{
+		List: append(readFileStmts(src.f), unmarshallWithStmt("yaml", tokenReceiver, tokenData)),
+	}, importSpecs("os", "gopkg.in/yaml.v3")
+}
+
+// normalizeTree converts the result of decoding a non-json format into an interface{} into the tree the Parser
+// walks, see TreeSource
+func normalizeTree(obj interface{}) (JsonObject, error) {
+	switch v := obj.(type) {
+	case map[string]interface{}:
+		m := make(JsonMap, len(v))
+		for key, value := range v {
+			normalized, err := normalizeTree(value)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = normalized
+		}
+		return m, nil
+	case map[interface{}]interface{}:
+		m := make(JsonMap, len(v))
+		for key, value := range v {
+			normalized, err := normalizeTree(value)
+			if err != nil {
+				return nil, err
+			}
+			m[fmt.Sprint(key)] = normalized
+		}
+		return m, nil
+	case []map[string]interface{}:
+		a := make(JsonArray, len(v))
+		for i, value := range v {
+			normalized, err := normalizeTree(value)
+			if err != nil {
+				return nil, err
+			}
+			a[i] = normalized
+		}
+		return a, nil
+	case []interface{}:
+		a := make(JsonArray, len(v))
+		for i, value := range v {
+			normalized, err := normalizeTree(value)
+			if err != nil {
+				return nil, err
+			}
+			a[i] = normalized
+		}
+		return a, nil
+	case int:
+		return json.Number(strconv.Itoa(v)), nil
+	case int64:
+		return json.Number(strconv.FormatInt(v, 10)), nil
+	case uint64:
+		return json.Number(strconv.FormatUint(v, 10)), nil
+	case float64:
+		f := strconv.FormatFloat(v, 'g', -1, 64)
+		if !strings.ContainsAny(f, ".eEIN") {
+			// Keep the number a float, instead of the Parser detecting an integer
+			f += ".0"
+		}
+		return json.Number(f), nil
+	case string, bool, nil, time.Time, json.Number:
+		return v, nil
+	}
+
+	return nil, fmt.Errorf("%w: can't normalize type %T", ErrUnknownType, obj)
+}
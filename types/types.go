@@ -0,0 +1,158 @@
+// Package types holds the types used by generated files for values encoding/json can't decode into a go type
+// directly. See the NativeTypeParser's in the yoitsu package
+package types
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DateLayout = time.DateOnly
+
+// Date is a time.Time (un)marshalled as "2006-01-02"
+type Date struct {
+	time.Time
+}
+
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.Format(DateLayout)), nil
+}
+
+func (d *Date) UnmarshalText(text []byte) error {
+	t, err := time.Parse(DateLayout, string(text))
+	if err != nil {
+		return err
+	}
+	d.Time = t
+	return nil
+}
+
+// MarshalJSON must be defined, as the promoted time.Time method would otherwise be used
+func (d Date) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.Format(DateLayout))), nil
+}
+
+// UnmarshalJSON must be defined, as the promoted time.Time method would otherwise be used
+func (d *Date) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return d.UnmarshalText([]byte(s))
+}
+
+// UnixTime is a time.Time (un)marshalled as the amount of seconds since the unix epoch
+type UnixTime struct {
+	time.Time
+}
+
+// MarshalText must be defined, as the promoted time.Time method would otherwise be used, e.g. by encoding/xml
+func (u UnixTime) MarshalText() ([]byte, error) {
+	return []byte(strconv.FormatInt(u.Unix(), 10)), nil
+}
+
+// UnmarshalText must be defined, as the promoted time.Time method would otherwise be used, e.g. by encoding/xml
+func (u *UnixTime) UnmarshalText(text []byte) error {
+	f, err := strconv.ParseFloat(string(text), 64)
+	if err != nil {
+		return err
+	}
+
+	sec, frac := math.Modf(f)
+	u.Time = time.Unix(int64(sec), int64(frac*float64(time.Second)))
+	return nil
+}
+
+func (u UnixTime) MarshalJSON() ([]byte, error) {
+	return u.MarshalText()
+}
+
+func (u *UnixTime) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+	return u.UnmarshalText(b)
+}
+
+// Duration is a time.Duration (un)marshalled as a string, see time.ParseDuration
+type Duration struct {
+	time.Duration
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	dur, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	d.Duration = dur
+	return nil
+}
+
+// URL is an url.URL (un)marshalled as a string
+type URL struct {
+	url.URL
+}
+
+func (u URL) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+func (u *URL) UnmarshalText(text []byte) error {
+	parsed, err := url.Parse(string(text))
+	if err != nil {
+		return err
+	}
+	u.URL = *parsed
+	return nil
+}
+
+// UUID is a 16 byte UUID (un)marshalled in its canonical xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx form
+type UUID [16]byte
+
+// ParseUUID parses the canonical form of an UUID, case-insensitive
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return u, fmt.Errorf("invalid UUID %q", s)
+	}
+
+	b, err := hex.DecodeString(strings.ReplaceAll(s, "-", ""))
+	if err != nil {
+		return u, fmt.Errorf("invalid UUID %q: %w", s, err)
+	}
+
+	copy(u[:], b)
+	return u, nil
+}
+
+func (u UUID) String() string {
+	h := hex.EncodeToString(u[:])
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
+
+func (u UUID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+func (u *UUID) UnmarshalText(text []byte) error {
+	parsed, err := ParseUUID(string(text))
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
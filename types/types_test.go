@@ -0,0 +1,97 @@
+package types
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+)
+
+// xmlValues has an element and an attribute of every type, as generated for -parsers with an xml source
+type xmlValues struct {
+	XMLName      xml.Name `xml:"values"`
+	Date         Date     `xml:"date"`
+	DateAttr     Date     `xml:"date,attr"`
+	UnixTime     UnixTime `xml:"unix"`
+	UnixTimeAttr UnixTime `xml:"unix,attr"`
+	Duration     Duration `xml:"duration"`
+	DurationAttr Duration `xml:"duration,attr"`
+	URL          URL      `xml:"url"`
+	URLAttr      URL      `xml:"url,attr"`
+	UUID         UUID     `xml:"uuid"`
+	UUIDAttr     UUID     `xml:"uuid,attr"`
+}
+
+const xmlDoc = `<values date="2024-02-29" unix="1700000000" duration="1m30s" url="https://example.com/a?b=c" uuid="123e4567-e89b-12d3-a456-426614174000">` +
+	`<date>2024-02-29</date>` +
+	`<unix>1700000000</unix>` +
+	`<duration>1m30s</duration>` +
+	`<url>https://example.com/a?b=c</url>` +
+	`<uuid>123e4567-e89b-12d3-a456-426614174000</uuid>` +
+	`</values>`
+
+func TestXmlRoundTrip(t *testing.T) {
+	var v xmlValues
+	if err := xml.Unmarshal([]byte(xmlDoc), &v); err != nil {
+		t.Fatalf("xml.Unmarshal: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"Date", v.Date.Format(DateLayout), "2024-02-29"},
+		{"Date attr", v.DateAttr.Format(DateLayout), "2024-02-29"},
+		{"UnixTime", v.UnixTime.UTC().String(), "2023-11-14 22:13:20 +0000 UTC"},
+		{"UnixTime attr", v.UnixTimeAttr.UTC().String(), "2023-11-14 22:13:20 +0000 UTC"},
+		{"Duration", v.Duration.String(), "1m30s"},
+		{"Duration attr", v.DurationAttr.String(), "1m30s"},
+		{"URL", v.URL.String(), "https://example.com/a?b=c"},
+		{"URL attr", v.URLAttr.String(), "https://example.com/a?b=c"},
+		{"UUID", v.UUID.String(), "123e4567-e89b-12d3-a456-426614174000"},
+		{"UUID attr", v.UUIDAttr.String(), "123e4567-e89b-12d3-a456-426614174000"},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+
+	b, err := xml.Marshal(v)
+	if err != nil {
+		t.Fatalf("xml.Marshal: %v", err)
+	}
+	if string(b) != xmlDoc {
+		t.Errorf("xml.Marshal:\ngot  %s\nwant %s", b, xmlDoc)
+	}
+}
+
+func TestUnixTimeJson(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"1700000000", 1700000000},
+		{"1700000000.5", 1700000000},
+		{"null", 0},
+	}
+
+	for _, tt := range tests {
+		var u UnixTime
+		if err := json.Unmarshal([]byte(tt.in), &u); err != nil {
+			t.Errorf("%s: %v", tt.in, err)
+			continue
+		}
+
+		if tt.want == 0 {
+			if !u.IsZero() {
+				t.Errorf("%s: got %v, want the zero time", tt.in, u.Time)
+			}
+			continue
+		}
+		if u.Unix() != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.in, u.Unix(), tt.want)
+		}
+	}
+}
@@ -1,11 +1,16 @@
 package yoitsu
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"go/ast"
 	"go/format"
+	"go/printer"
 	"go/token"
+	"io"
 	"os"
 	"path/filepath"
 	"slices"
@@ -17,12 +22,12 @@ type Metadata struct {
 
 type Accessors struct {
 	// Generate an Accessor struct, loads data from Source
-	Generate bool
+	Generate bool `json:"generate"`
 	// If the jsons is of type JsonArray, will generate methods based on unique fields in the root of the
 	// JsonObjects in the array
-	ById bool
+	ById bool `json:"byId"`
 	// Unused
-	GroupByPrimitive bool
+	GroupByPrimitive bool `json:"groupByPrimitive"`
 }
 
 type Yoitsu struct {
@@ -30,13 +35,29 @@ type Yoitsu struct {
 	File       *ast.File
 	noFileData bool
 
-	src       Source
-	metadata  Metadata
-	universe  Universe
-	accessors Accessors
-
+	src            Source
+	metadata       Metadata
+	universe       Universe
+	accessors      Accessors
+	optionalFields OptionalFields
+	unions         Unions
+	streaming      bool
+	sampling       Sampling
+	// structTags are the keys of the struct tags emitted on every field, see StructTagsSource
+	structTags []string
+
+	// roots holds the decoded samples, root is all roots combined. See combineRoots
+	roots  []interface{}
 	root   interface{}
 	parser *Parser
+	// uniqueValues is populated while streaming if Accessors.ById is set, see Yoitsu.uniqueJsonPrimitives
+	uniqueValues *uniqueValues
+	// sample describes the elements of root JsonArray's parsed, see Sampling
+	sample Sample
+	// rootType is the generated type of root, populated after calling Yoitsu.GenerateFile
+	rootType GeneratedType
+	// optErr collects errors from options, returned by Yoitsu.GenerateFile
+	optErr error
 }
 
 // WithUniverse includes the passes Universe in Yoitsu.
@@ -61,6 +82,43 @@ func WithGenerateAccessors(accessorOpt Option[*Accessors]) Option[*Yoitsu] {
 	}
 }
 
+// WithOptionalFields sets how fields missing or null in some samples are emitted, defaults to OptionalFieldsPlain
+func WithOptionalFields(optional OptionalFields) Option[*Yoitsu] {
+	return func(y *Yoitsu) {
+		y.optionalFields = optional
+	}
+}
+
+// WithUnions sets how values with different types across samples are emitted, defaults to UnionsError
+func WithUnions(unions Unions) Option[*Yoitsu] {
+	return func(y *Yoitsu) {
+		y.unions = unions
+	}
+}
+
+// WithStreaming reads sources implementing StreamSource token by token, instead of decoding all json into memory
+// first. Elements of arrays are merged as they're read, so memory is proportional to the generated types rather
+// than the data. Accessors.ById only keeps the values of fields which can still be unique.
+//
+// Ignored for a TypeSource, other sources are decoded as usual
+func WithStreaming() Option[*Yoitsu] {
+	return func(y *Yoitsu) {
+		y.streaming = true
+	}
+}
+
+// WithSampling only infers the types from some elements of a root JsonArray, for arrays too large to parse every
+// element. The generated file and Yoitsu.JsonSchema note the type was inferred from a sample, see Yoitsu.Sample.
+//
+// Accessors.ById still considers every element. When streaming, the skipped elements are never decoded, so no ById
+// methods are generated for a sampled root JsonArray. Yoitsu.GenerateFile returns ErrInvalidConfig if Sampling.Bytes
+// is set without WithStreaming
+func WithSampling(sampling Sampling) Option[*Yoitsu] {
+	return func(y *Yoitsu) {
+		y.sampling = sampling
+	}
+}
+
 // WithMetadata to further customize Metadata, currently unused
 func WithMetadata(metaOpt Option[*Metadata]) Option[*Yoitsu] {
 	return func(y *Yoitsu) {
@@ -68,6 +126,24 @@ func WithMetadata(metaOpt Option[*Metadata]) Option[*Yoitsu] {
 	}
 }
 
+// WithNativeTypeParser registers the NativeTypeParser on the Parser. See Parser.RegisterNativeType
+func WithNativeTypeParser(parent GeneratedType, f NativeTypeParser) Option[*Yoitsu] {
+	return func(y *Yoitsu) {
+		if err := y.parser.RegisterNativeType(parent, f); err != nil {
+			y.optErr = errors.Join(y.optErr, err)
+		}
+	}
+}
+
+// WithDefaultNativeParsers registers all DefaultNativeParsers
+func WithDefaultNativeParsers() Option[*Yoitsu] {
+	return func(y *Yoitsu) {
+		for _, np := range DefaultNativeParsers {
+			WithNativeTypeParser(np.Parent, np.Parser)(y)
+		}
+	}
+}
+
 // New create a new Yoitsu instance, it is recommended to create a new one per generation.
 // See documentation for options on how to customize the output
 func New(src Source, opts ...Option[*Yoitsu]) *Yoitsu {
@@ -76,11 +152,22 @@ func New(src Source, opts ...Option[*Yoitsu]) *Yoitsu {
 		metadata:  Metadata{},
 		accessors: Accessors{},
 	}
+	yt.parser = NewParser(yt)
+
+	yt.structTags = []string{"json"}
+	if tagsSrc, ok := src.(StructTagsSource); ok {
+		yt.structTags = append(yt.structTags, tagsSrc.StructTags()...)
+	}
 
 	for _, opt := range opts {
 		opt(yt)
 	}
 
+	if yt.sampling.Bytes > 0 && !yt.streaming {
+		// Options may be passed in any order, so this can't be checked in WithSampling
+		yt.optErr = errors.Join(yt.optErr, fmt.Errorf("%w: Sampling.Bytes requires WithStreaming", ErrInvalidConfig))
+	}
+
 	if yt.metadata.packageName == "" {
 		yt.metadata.packageName = "generated"
 	}
@@ -89,30 +176,110 @@ func New(src Source, opts ...Option[*Yoitsu]) *Yoitsu {
 		yt.universe = EmptyUniverse()
 	}
 
-	yt.parser = NewParser(yt)
 	return yt
 }
 
-func (y *Yoitsu) getRootFromSrc() (interface{}, error) {
-	b, err := y.src.Json()
+// getRootsFromSrc decodes every sample of the Source, see SamplesSource
+func (y *Yoitsu) getRootsFromSrc(ctx context.Context) ([]interface{}, error) {
+	samples := []Source{y.src}
+	if ss, ok := y.src.(SamplesSource); ok {
+		var err error
+		if samples, err = ss.Samples(); err != nil {
+			return nil, err
+		}
+	}
+
+	roots := make([]interface{}, 0, len(samples))
+	for _, sample := range samples {
+		root, err := getRootFromSrc(ctx, sample)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", sample.Name(), err)
+		}
+		roots = append(roots, root)
+	}
+
+	return roots, nil
+}
+
+// combineRoots returns the single root, or all roots concatenated if they're all JsonArray's. Otherwise, nil
+func combineRoots(roots []interface{}) interface{} {
+	if len(roots) == 1 {
+		return roots[0]
+	}
+
+	var combined JsonArray
+	for _, root := range roots {
+		array, ok := root.(JsonArray)
+		if !ok {
+			return nil
+		}
+		combined = append(combined, array...)
+	}
+	return combined
+}
+
+func getRootFromSrc(ctx context.Context, src Source) (interface{}, error) {
+	if treeSrc, ok := src.(TreeSource); ok {
+		return treeSrc.Root()
+	}
+
+	var (
+		b   []byte
+		err error
+	)
+
+	if ctxSrc, ok := src.(ContextSource); ok {
+		b, err = ctxSrc.JsonContext(ctx)
+	} else {
+		b, err = src.Json()
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	return decodeJson(b)
+}
+
+// decodeJson decodes the single json value in b
+func decodeJson(b []byte) (JsonObject, error) {
+	// UseNumber so the Parser can distinguish integers, and large integers do not lose precision
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+
 	var root interface{}
-	err = json.Unmarshal(b, &root)
-	if err != nil {
+	if err := decoder.Decode(&root); err != nil {
 		return nil, err
 	}
 
+	// Decode stops after the first value, json.Unmarshal rejects anything after it
+	if _, err := decoder.Token(); err != io.EOF {
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("unexpected data after the top-level value at offset %d", decoder.InputOffset())
+	}
+
 	return root, nil
 }
 
 // GenerateFile parses the json from Source, and sets the Yoitsu.File field
-func (y *Yoitsu) GenerateFile() (err error) {
-	y.root, err = y.getRootFromSrc()
-	if err != nil {
-		return
+func (y *Yoitsu) GenerateFile() error {
+	return y.GenerateFileContext(context.Background())
+}
+
+// GenerateFileContext is GenerateFile, passing the context to any ContextSource
+func (y *Yoitsu) GenerateFileContext(ctx context.Context) (err error) {
+	if y.optErr != nil {
+		return y.optErr
+	}
+
+	y.sample = Sample{}
+	if !y.streamed() {
+		y.roots, err = y.getRootsFromSrc(ctx)
+		if err != nil {
+			return
+		}
+		y.root = combineRoots(y.roots)
 	}
 
 	var (
@@ -124,25 +291,42 @@ func (y *Yoitsu) GenerateFile() (err error) {
 		accessorDecls   []ast.Decl
 	)
 
-	gType, importSpecs, structDecls, err = y.generateJsonTypes()
+	gType, importSpecs, structDecls, err = y.generateJsonTypes(ctx)
 	if err != nil {
 		return
 	}
+	y.rootType = gType
+
+	// A typeSet has multiple roots, the sampled one is unknown
+	if _, isSet := gType.(*typeSet); !isSet && len(structDecls) > 0 {
+		if sample, ok := y.Sample(); ok {
+			noteSample(structDecls[0], sample)
+		}
+	}
 
 	accessorDecls, accessorImports, err = y.generateMethodAccessors(gType)
 	if err != nil {
 		return
 	}
 
+	if declsSrc, ok := y.src.(DeclsSource); ok {
+		srcDecls, srcImports := declsSrc.Decls()
+		structDecls = append(structDecls, srcDecls...)
+		importSpecs = append(importSpecs, srcImports...)
+	}
+
 	var decls []ast.Decl
 	var allImportSpecs []ast.Spec
 
-	if len(importSpecs) > 0 {
-		allImportSpecs = append(allImportSpecs, importSpecs...)
-	}
+	var addedImports []string
+	for _, spec := range append(importSpecs, accessorImports...) {
+		path := spec.(*ast.ImportSpec).Path.Value
+		if slices.Contains(addedImports, path) {
+			continue
+		}
 
-	if len(accessorImports) > 0 {
-		allImportSpecs = append(allImportSpecs, accessorImports...)
+		allImportSpecs = append(allImportSpecs, spec)
+		addedImports = append(addedImports, path)
 	}
 
 	if len(allImportSpecs) > 0 {
@@ -179,17 +363,106 @@ func (y *Yoitsu) GenerateFile() (err error) {
 	return nil
 }
 
-func (y *Yoitsu) generateJsonTypes() (gType GeneratedType, importSpecs []ast.Spec, structDecls []ast.Decl, err error) {
-	gType, err = y.parser.ParseRoot(y.src.Name(), y.root)
+// Sample returns the elements of the root JsonArray's the types were inferred from, and true if that wasn't every
+// element. See WithSampling
+func (y *Yoitsu) Sample() (Sample, bool) {
+	return y.sample, y.sample.Truncated || y.sample.Elements < y.sample.Read
+}
+
+// noteSample documents the first generated type was inferred from the sample
+func noteSample(decl ast.Decl, sample Sample) {
+	genDecl, ok := decl.(*ast.GenDecl)
+	if !ok || genDecl.Tok != token.TYPE || genDecl.Doc != nil {
+		return
+	}
+
+	genDecl.Doc = &ast.CommentGroup{
+		List: []*ast.Comment{
+			{
+				Text: fmt.Sprintf("\n// %s was inferred from %s", genDecl.Specs[0].(*ast.TypeSpec).Name.Name, sample),
+			},
+		},
+	}
+}
+
+// streamed returns true if the types are generated by Yoitsu.streamJsonTypes
+func (y *Yoitsu) streamed() bool {
+	_, ok := y.src.(TypeSource)
+	return y.streaming && !ok
+}
+
+func (y *Yoitsu) generateJsonTypes(ctx context.Context) (gType GeneratedType, importSpecs []ast.Spec, structDecls []ast.Decl, err error) {
+	if typeSrc, ok := y.src.(TypeSource); ok {
+		gType, err = typeSrc.GeneratedType(y.parser)
+	} else if y.streamed() {
+		gType, err = y.streamJsonTypes(ctx)
+	} else {
+		gType, err = y.parser.ParseRoots(y.src.Name(), y.roots...)
+	}
 	if err != nil {
 		return
 	}
 
-	structDecls = gType.Representation()
+	if !gType.IsComplexObject() {
+		return gType, nil, nil, nil
+	}
+
+	if structDecls, err = uniqueTypeDecls(gType.Representation()); err != nil {
+		return
+	}
 	importSpecs = y.imports(gType)
 	return
 }
 
+// uniqueTypeDecls removes repeated declarations of the same type, which happens when a type is used more than once,
+// e.g. through a $ref. Methods are deduplicated on their receiver. Different declarations with the same name return
+// ErrCantMergeDifferentTypes
+func uniqueTypeDecls(decls []ast.Decl) ([]ast.Decl, error) {
+	var unique []ast.Decl
+	declared := make(map[string]string)
+
+	for _, decl := range decls {
+		var name string
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			if d.Tok == token.TYPE && len(d.Specs) == 1 {
+				name = d.Specs[0].(*ast.TypeSpec).Name.Name
+			}
+		case *ast.FuncDecl:
+			if d.Recv != nil && len(d.Recv.List) == 1 {
+				name = printNode(d.Recv.List[0].Type) + "." + d.Name.Name
+			}
+		}
+
+		if name == "" {
+			unique = append(unique, decl)
+			continue
+		}
+
+		src := printNode(decl)
+		if prev, ok := declared[name]; ok {
+			if prev != src {
+				return nil, fmt.Errorf("%w: %s is declared as\n%s\nand\n%s", ErrCantMergeDifferentTypes, name, prev, src)
+			}
+			continue
+		}
+
+		declared[name] = src
+		unique = append(unique, decl)
+	}
+
+	return unique, nil
+}
+
+// printNode returns the go source of the node
+func printNode(node ast.Node) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, token.NewFileSet(), node); err != nil {
+		return fmt.Sprintf("%#v", node)
+	}
+	return buf.String()
+}
+
 func (y *Yoitsu) imports(gType GeneratedType) (imports []ast.Spec) {
 	var addedImports []string
 